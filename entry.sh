@@ -1,5 +1,10 @@
 #!/bin/sh
 
+# LOCAL は true/false (1/0 等) で指定する
+case "${LOCAL}" in
+    ""|0|f|F|false|FALSE|False) LOCAL= ;;
+esac
+
 if [ -z "${AWS_LAMBDA_RUNTIME_API}" ] && [ -z "${LOCAL}" ]; then
     exec /usr/bin/aws-lambda-rie "$@"
 else
@@ -0,0 +1,649 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SongCastle/ggnb/cron"
+	"github.com/SongCastle/ggnb/income/i18n"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// 設定ファイル (YAML もしくは JSON) のパス
+	FileEnv = "CONFIG_FILE"
+	// 設定ファイルの変更を確認する間隔 (秒)
+	ReloadIntervalEnv = "CONFIG_RELOAD_INTERVAL"
+	ReloadAnnounceEnv = "CONFIG_RELOAD_ANNOUNCE"
+	ReloadRouteEnv = "CONFIG_RELOAD_ROUTE"
+
+	LocalEnv = "LOCAL"
+	IncomeTypeEnv = "INCOME_TYPE"
+	WebHookUrlEnv = "SLACK_WEBHOOK_URL"
+	// route ごとの WebHook URL (SLACK_WEBHOOK_URL_<ROUTE>)
+	RouteWebHookUrlEnvPrefix = WebHookUrlEnv + "_"
+	TokenEnv = "GITHUB_TOKEN"
+	BaseUrlEnv = "GITHUB_API_URL"
+	StoreDirEnv = "STORE_DIR"
+	AuditRouteEnv = "AUDIT_ROUTE"
+	SponsorRouteEnv = "SPONSOR_ROUTE"
+	LocaleEnv = "LOCALE"
+	// route ごとの言語 (LOCALE_<ROUTE>)
+	RouteLocaleEnvPrefix = "LOCALE_"
+	StarBatchSizeEnv = "STAR_BATCH_SIZE"
+	StarMilestonesEnv = "STAR_MILESTONES"
+	PushCommitLimitEnv = "PUSH_COMMIT_LIMIT"
+	DraftPullRequestEnv = "DRAFT_PULL_REQUEST"
+	MentionsEnv = "SLACK_MENTIONS"
+	PackageFilterEnv = "PACKAGE_FILTER"
+	MarkdownFormatEnv = "MARKDOWN_FORMAT"
+	RenderUnhandledEnv = "RENDER_UNHANDLED_EVENTS"
+	TemplateDirEnv = "TEMPLATE_DIR"
+	FilterRulesEnv = "FILTER_RULES"
+	FilterDryRunEnv = "FILTER_DRY_RUN"
+	RepoConfigPathEnv = "REPO_CONFIG_PATH"
+	RepoConfigAllowEnv = "REPO_CONFIG_ALLOW"
+	QuietHoursUrgentEnv = "QUIET_HOURS_URGENT"
+
+	GitHubType = "github"
+	// Draft PR の通知方法
+	DraftMute = "mute"
+	DraftSuppress = "suppress"
+	DraftShow = "show"
+	// quiet_hours.routes・digest.routes で route ごとの設定がない通知先
+	DefaultDestination = "default"
+	// quiet_hours.routes.<route>.holidays の形式
+	HolidayLayout = "2006-01-02"
+)
+
+var DefaultStarMilestones = []int{100, 500, 1000, 5000, 10000}
+
+type Config struct {
+	// 読み込んだ設定ファイル
+	File string `yaml:"-"`
+	Reload Reload `yaml:"reload"`
+	// 設定されている場合、Lambda ではなくローカルで実行する
+	Local bool `yaml:"local"`
+	Income Income `yaml:"income"`
+	Slack Slack `yaml:"slack"`
+	GitHub GitHub `yaml:"github"`
+	Store Store `yaml:"store"`
+	Routes Routes `yaml:"routes"`
+	Locale Locale `yaml:"locale"`
+	Message Message `yaml:"message"`
+	Filter Filter `yaml:"filter"`
+	Repo Repo `yaml:"repo"`
+	QuietHours QuietHours `yaml:"quiet_hours"`
+	Digest Digest `yaml:"digest"`
+}
+
+type Reload struct {
+	// 0 の場合は読み込み直さない
+	Interval int `yaml:"interval"`
+	// 設定されている場合、読み込み直したことを route に通知する
+	Announce bool `yaml:"announce"`
+	Route string `yaml:"route"`
+}
+
+type Income struct {
+	Type string `yaml:"type"`
+}
+
+type Slack struct {
+	WebHookUrl string `yaml:"webhook_url"`
+	// route から WebHook URL
+	Routes map[string]string `yaml:"routes"`
+}
+
+type GitHub struct {
+	// 設定されていない場合は GitHub API を利用しない
+	Token string `yaml:"token"`
+	ApiUrl string `yaml:"api_url"`
+}
+
+type Store struct {
+	Dir string `yaml:"dir"`
+}
+
+// 通知先を振り分ける route
+type Routes struct {
+	Audit string `yaml:"audit"`
+	Sponsor string `yaml:"sponsor"`
+}
+
+type Locale struct {
+	Default string `yaml:"default"`
+	// route から言語
+	Routes map[string]string `yaml:"routes"`
+}
+
+type Message struct {
+	StarBatchSize int `yaml:"star_batch_size"`
+	StarMilestones []int `yaml:"star_milestones"`
+	PushCommitLimit int `yaml:"push_commit_limit"`
+	DraftPullRequest string `yaml:"draft_pull_request"`
+	// GitHub の login もしくは email から Slack のユーザー ID
+	Mentions map[string]string `yaml:"mentions"`
+	PackageFilter []string `yaml:"package_filter"`
+	MarkdownFormat string `yaml:"markdown_format"`
+	RenderUnhandledEvents bool `yaml:"render_unhandled_events"`
+	TemplateDir string `yaml:"template_dir"`
+}
+
+type Filter struct {
+	Rules []string `yaml:"rules"`
+	DryRun bool `yaml:"dry_run"`
+}
+
+// レポジトリの設定ファイル
+type Repo struct {
+	// 空の場合は読み込まない (github.token が必要)
+	Path string `yaml:"path"`
+	// 上書きできる項目 (RepoRoute, RepoFilter, RepoMentions)
+	Allow []string `yaml:"allow"`
+}
+
+// 通知を控える時間帯 (その間の通知は、時間帯が終わった後にまとめて通知する)
+type QuietHours struct {
+	// route (DefaultDestination はそれ以外の通知先) ごとの時間帯
+	Routes map[string]QuietWindow `yaml:"routes"`
+	// 時間帯に関わらず通知する Event (filter.rules と同じ形式の include ルール)
+	Urgent []string `yaml:"urgent"`
+}
+
+type QuietWindow struct {
+	// 未設定の場合は UTC
+	Timezone string `yaml:"timezone"`
+	// HH:MM (Start が End より後の場合は日付をまたぐ)
+	Start string `yaml:"start"`
+	End string `yaml:"end"`
+	// 終日通知を控える曜日 (sun, mon, ...)
+	Weekdays []string `yaml:"weekdays"`
+	// 終日通知を控える日付 (HolidayLayout)
+	Holidays []string `yaml:"holidays"`
+}
+
+// 個別に通知せず、定期的に概要をまとめて通知する
+type Digest struct {
+	// route (DefaultDestination はそれ以外の通知先) ごとの通知する時刻
+	Routes map[string]DigestSchedule `yaml:"routes"`
+}
+
+type DigestSchedule struct {
+	// cron 式 (分 時 日 月 曜日) もしくは @daily, @weekly 等
+	Schedule string `yaml:"schedule"`
+	// 未設定の場合は UTC
+	Timezone string `yaml:"timezone"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// HH:MM を 0:00 からの分に変換する
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("%q is not HH:MM", s))
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func ParseWeekday(s string) (time.Weekday, error) {
+	d, ok := weekdays[strings.ToLower(s)]
+	if !ok {
+		return 0, errors.New(fmt.Sprintf("%q is not one of sun, mon, tue, wed, thu, fri, sat", s))
+	}
+	return d, nil
+}
+
+// CONFIG_FILE (設定されている場合) を読み込み、環境変数で上書きする
+func Load() (*Config, error) {
+	return load(os.Getenv(FileEnv))
+}
+
+func load(file string) (*Config, error) {
+	c := &Config{File: file}
+	if file != "" {
+		if err := c.readFile(file); err != nil {
+			return nil, err
+		}
+	}
+	// route は大文字・小文字を区別しない
+	c.Slack.Routes = lowerKeys(c.Slack.Routes)
+	c.Locale.Routes = lowerKeys(c.Locale.Routes)
+	if c.QuietHours.Routes != nil {
+		windows := make(map[string]QuietWindow, len(c.QuietHours.Routes))
+		for route, w := range c.QuietHours.Routes {
+			windows[strings.ToLower(route)] = w
+		}
+		c.QuietHours.Routes = windows
+	}
+	if c.Digest.Routes != nil {
+		schedules := make(map[string]DigestSchedule, len(c.Digest.Routes))
+		for route, d := range c.Digest.Routes {
+			schedules[strings.ToLower(route)] = d
+		}
+		c.Digest.Routes = schedules
+	}
+	if err := c.applyEnv(); err != nil {
+		return nil, err
+	}
+	if len(c.Message.StarMilestones) == 0 {
+		c.Message.StarMilestones = append([]int{}, DefaultStarMilestones...)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Config) readFile(file string) error {
+	text, err := os.ReadFile(file)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Invalid %s: %v", FileEnv, err))
+	}
+	expanded, err := interpolate(text)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Invalid %s (%s): %v", FileEnv, file, err))
+	}
+	d := yaml.NewDecoder(bytes.NewReader(expanded))
+	// 存在しない項目はエラーにする
+	d.KnownFields(true)
+	if err := d.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return errors.New(fmt.Sprintf("Invalid %s (%s): %v", FileEnv, file, err))
+	}
+	return nil
+}
+
+var variable = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// 値に含まれる ${VAR} を環境変数で置き換える (YAML として解釈した後に置き換える)
+func interpolate(text []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(text, &doc); err != nil {
+		return nil, err
+	}
+	if err := expandNode(&doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return yaml.Marshal(&doc)
+}
+
+func expandNode(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode && variable.MatchString(n.Value) {
+		var err error
+		n.Value = variable.ReplaceAllStringFunc(n.Value, func(s string) string {
+			name := variable.FindStringSubmatch(s)[1]
+			v, ok := os.LookupEnv(name)
+			if !ok && err == nil {
+				err = errors.New(fmt.Sprintf("line %d: %s is not set", n.Line, name))
+			}
+			return v
+		})
+		if err != nil {
+			return err
+		}
+		// 置き換えた値の型は YAML として解釈し直す
+		n.Tag = ""
+		n.Style = 0
+	}
+	for _, child := range n.Content {
+		if err := expandNode(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 環境変数と設定ファイルの項目
+type setting struct {
+	env string
+	key string
+	set func(string) error
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{ReloadIntervalEnv, "reload.interval", setInt(&c.Reload.Interval)},
+		{ReloadAnnounceEnv, "reload.announce", setFlag(&c.Reload.Announce)},
+		{ReloadRouteEnv, "reload.route", setString(&c.Reload.Route)},
+		{LocalEnv, "local", setFlag(&c.Local)},
+		{IncomeTypeEnv, "income.type", setString(&c.Income.Type)},
+		{WebHookUrlEnv, "slack.webhook_url", setString(&c.Slack.WebHookUrl)},
+		{TokenEnv, "github.token", setString(&c.GitHub.Token)},
+		{BaseUrlEnv, "github.api_url", setString(&c.GitHub.ApiUrl)},
+		{StoreDirEnv, "store.dir", setString(&c.Store.Dir)},
+		{AuditRouteEnv, "routes.audit", setString(&c.Routes.Audit)},
+		{SponsorRouteEnv, "routes.sponsor", setString(&c.Routes.Sponsor)},
+		{LocaleEnv, "locale.default", setString(&c.Locale.Default)},
+		{StarBatchSizeEnv, "message.star_batch_size", setInt(&c.Message.StarBatchSize)},
+		{StarMilestonesEnv, "message.star_milestones", setInts(&c.Message.StarMilestones)},
+		{PushCommitLimitEnv, "message.push_commit_limit", setInt(&c.Message.PushCommitLimit)},
+		{DraftPullRequestEnv, "message.draft_pull_request", setString(&c.Message.DraftPullRequest)},
+		{MentionsEnv, "message.mentions", setPairs(&c.Message.Mentions)},
+		{PackageFilterEnv, "message.package_filter", setList(&c.Message.PackageFilter, ",")},
+		{MarkdownFormatEnv, "message.markdown_format", setString(&c.Message.MarkdownFormat)},
+		{RenderUnhandledEnv, "message.render_unhandled_events", setFlag(&c.Message.RenderUnhandledEvents)},
+		{TemplateDirEnv, "message.template_dir", setString(&c.Message.TemplateDir)},
+		{FilterRulesEnv, "filter.rules", setList(&c.Filter.Rules, ";\n")},
+		{FilterDryRunEnv, "filter.dry_run", setFlag(&c.Filter.DryRun)},
+		{RepoConfigPathEnv, "repo.path", setString(&c.Repo.Path)},
+		{RepoConfigAllowEnv, "repo.allow", setList(&c.Repo.Allow, ",")},
+		{QuietHoursUrgentEnv, "quiet_hours.urgent", setList(&c.QuietHours.Urgent, ";\n")},
+	}
+}
+
+// 空でない環境変数で設定ファイルの値を上書きする
+func (c *Config) applyEnv() error {
+	for _, s := range c.settings() {
+		v := os.Getenv(s.env)
+		if v == "" {
+			continue
+		}
+		if err := s.set(v); err != nil {
+			return invalid(s.key, s.env, err.Error())
+		}
+	}
+	for _, env := range os.Environ() {
+		kv := strings.SplitN(env, "=", 2)
+		if len(kv) != 2 || kv[1] == "" || !strings.HasPrefix(kv[0], RouteWebHookUrlEnvPrefix) {
+			continue
+		}
+		if c.Slack.Routes == nil {
+			c.Slack.Routes = map[string]string{}
+		}
+		c.Slack.Routes[strings.ToLower(strings.TrimPrefix(kv[0], RouteWebHookUrlEnvPrefix))] = kv[1]
+	}
+	// LOCALE_ から始まる環境変数は他にもあるため、設定されている route のみ確認する
+	for _, route := range []string{c.Routes.Audit, c.Routes.Sponsor} {
+		if route == "" {
+			continue
+		}
+		if locale := os.Getenv(RouteLocaleEnvPrefix + strings.ToUpper(route)); locale != "" {
+			if c.Locale.Routes == nil {
+				c.Locale.Routes = map[string]string{}
+			}
+			c.Locale.Routes[strings.ToLower(route)] = locale
+		}
+	}
+	return nil
+}
+
+func setString(p *string) func(string) error {
+	return func(v string) error {
+		*p = v
+		return nil
+	}
+}
+
+// true/false (1/0 等、strconv.ParseBool の形式)
+func setFlag(p *bool) func(string) error {
+	return func(v string) error {
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return errors.New(fmt.Sprintf("%q is not a boolean", v))
+		}
+		*p = b
+		return nil
+	}
+}
+
+func setInt(p *int) func(string) error {
+	return func(v string) error {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return errors.New(fmt.Sprintf("%q is not a number", v))
+		}
+		*p = n
+		return nil
+	}
+}
+
+func setInts(p *[]int) func(string) error {
+	return func(v string) error {
+		var ns []int
+		for _, s := range strings.Split(v, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				return errors.New(fmt.Sprintf("%q is not a number", s))
+			}
+			ns = append(ns, n)
+		}
+		*p = ns
+		return nil
+	}
+}
+
+// seps のいずれかの文字で区切る
+func setList(p *[]string, seps string) func(string) error {
+	return func(v string) error {
+		var list []string
+		for _, s := range strings.FieldsFunc(v, func(r rune) bool { return strings.ContainsRune(seps, r) }) {
+			if s = strings.TrimSpace(s); s != "" {
+				list = append(list, s)
+			}
+		}
+		*p = list
+		return nil
+	}
+}
+
+// <key>=<value>,<key>=<value>
+func setPairs(p *map[string]string) func(string) error {
+	return func(v string) error {
+		pairs := map[string]string{}
+		for _, kv := range strings.Split(v, ",") {
+			pair := strings.SplitN(kv, "=", 2)
+			if len(pair) != 2 || strings.TrimSpace(pair[0]) == "" || strings.TrimSpace(pair[1]) == "" {
+				return errors.New(fmt.Sprintf("%q is not <key>=<value>", kv))
+			}
+			pairs[strings.TrimSpace(pair[0])] = strings.TrimSpace(pair[1])
+		}
+		*p = pairs
+		return nil
+	}
+}
+
+func invalid(key, env, reason string) error {
+	return errors.New(fmt.Sprintf("Invalid %s (%s): %s", key, env, reason))
+}
+
+// 利用する側 (Markdown の形式やフィルタのルール等) で検証する設定のエラー
+func Invalid(key, env, reason string) error {
+	return invalid(key, env, reason)
+}
+
+func (c *Config) Validate() error {
+	if c.Income.Type != GitHubType {
+		return invalid("income.type", IncomeTypeEnv, fmt.Sprintf("%q is not supported (%s)", c.Income.Type, GitHubType))
+	}
+	if c.Slack.WebHookUrl == "" {
+		return invalid("slack.webhook_url", WebHookUrlEnv, "required")
+	}
+	for _, route := range sortedKeys(c.Slack.Routes) {
+		if c.Slack.Routes[route] == "" {
+			return invalid("slack.routes."+route, RouteWebHookUrlEnvPrefix+strings.ToUpper(route), "required")
+		}
+	}
+	if c.Reload.Interval < 0 {
+		return invalid("reload.interval", ReloadIntervalEnv, "must not be negative")
+	}
+	if err := validateLocale("locale.default", LocaleEnv, c.Locale.Default); err != nil {
+		return err
+	}
+	for _, route := range sortedKeys(c.Locale.Routes) {
+		if err := validateLocale("locale.routes."+route, RouteLocaleEnvPrefix+strings.ToUpper(route), c.Locale.Routes[route]); err != nil {
+			return err
+		}
+	}
+	for _, r := range []struct {
+		key string
+		env string
+		route string
+	}{
+		{"routes.audit", AuditRouteEnv, c.Routes.Audit},
+		{"routes.sponsor", SponsorRouteEnv, c.Routes.Sponsor},
+		{"reload.route", ReloadRouteEnv, c.Reload.Route},
+	} {
+		if err := c.validateRoute(r.key, r.env, r.route); err != nil {
+			return err
+		}
+	}
+
+	m := c.Message
+	if m.StarBatchSize < 0 {
+		return invalid("message.star_batch_size", StarBatchSizeEnv, "must not be negative")
+	}
+	for _, n := range m.StarMilestones {
+		if n <= 0 {
+			return invalid("message.star_milestones", StarMilestonesEnv, "must be positive")
+		}
+	}
+	if m.PushCommitLimit < 0 {
+		return invalid("message.push_commit_limit", PushCommitLimitEnv, "must not be negative")
+	}
+	switch m.DraftPullRequest {
+	case "", DraftMute, DraftSuppress, DraftShow:
+	default:
+		return invalid("message.draft_pull_request", DraftPullRequestEnv, fmt.Sprintf("%q is not one of %s, %s, %s", m.DraftPullRequest, DraftMute, DraftSuppress, DraftShow))
+	}
+	for login, id := range m.Mentions {
+		if login == "" || id == "" {
+			return invalid("message.mentions", MentionsEnv, "login and Slack user ID are required")
+		}
+	}
+	for _, p := range m.PackageFilter {
+		if _, err := path.Match(p, ""); p == "" || err != nil {
+			return invalid("message.package_filter", PackageFilterEnv, fmt.Sprintf("%q is not a valid pattern", p))
+		}
+	}
+
+	if c.Repo.Path != "" && c.GitHub.Token == "" {
+		return invalid("repo.path", RepoConfigPathEnv, fmt.Sprintf("%s is required", TokenEnv))
+	}
+	for _, key := range c.Repo.Allow {
+		if !repoKeys[key] {
+			return invalid("repo.allow", RepoConfigAllowEnv, fmt.Sprintf("%q is not one of %s, %s, %s", key, RepoRoute, RepoFilter, RepoMentions))
+		}
+	}
+
+	routes := make([]string, 0, len(c.QuietHours.Routes))
+	for route := range c.QuietHours.Routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		if err := c.QuietHours.Routes[route].validate(); err != nil {
+			return invalid("quiet_hours.routes."+route, FileEnv, err.Error())
+		}
+		if err := c.validateRoute("quiet_hours.routes."+route, FileEnv, route); err != nil {
+			return err
+		}
+	}
+
+	routes = routes[:0]
+	for route := range c.Digest.Routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		d := c.Digest.Routes[route]
+		if _, err := cron.Parse(d.Schedule); err != nil {
+			return invalid("digest.routes."+route+".schedule", FileEnv, err.Error())
+		}
+		if _, err := time.LoadLocation(d.Timezone); err != nil {
+			return invalid("digest.routes."+route+".timezone", FileEnv, fmt.Sprintf("%q is not a valid timezone", d.Timezone))
+		}
+		if err := c.validateRoute("digest.routes."+route, FileEnv, route); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w QuietWindow) validate() error {
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		return errors.New(fmt.Sprintf("%q is not a valid timezone", w.Timezone))
+	}
+	if (w.Start == "") != (w.End == "") {
+		return errors.New("start and end are required together")
+	}
+	if w.Start != "" {
+		for _, s := range []string{w.Start, w.End} {
+			if _, err := ParseClock(s); err != nil {
+				return err
+			}
+		}
+	}
+	for _, d := range w.Weekdays {
+		if _, err := ParseWeekday(d); err != nil {
+			return err
+		}
+	}
+	for _, d := range w.Holidays {
+		if _, err := time.Parse(HolidayLayout, d); err != nil {
+			return errors.New(fmt.Sprintf("%q is not %s", d, HolidayLayout))
+		}
+	}
+	return nil
+}
+
+// 通知先の route に WebHook URL が設定されていること (空・default はデフォルトの WebHook URL)
+func (c *Config) validateRoute(key, env, route string) error {
+	route = strings.ToLower(route)
+	if route == "" || route == DefaultDestination || c.Slack.Routes[route] != "" {
+		return nil
+	}
+	return invalid(key, env, fmt.Sprintf("%s is not set", RouteWebHookUrlEnvPrefix+strings.ToUpper(route)))
+}
+
+func validateLocale(key, env, locale string) error {
+	if locale == "" {
+		return nil
+	}
+	for _, l := range i18n.Locales() {
+		if l == locale {
+			return nil
+		}
+	}
+	return invalid(key, env, fmt.Sprintf("%q is not one of %s", locale, strings.Join(i18n.Locales(), ", ")))
+}
+
+func lowerKeys(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	lower := make(map[string]string, len(m))
+	for k, v := range m {
+		lower[strings.ToLower(k)] = v
+	}
+	return lower
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,302 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 環境変数を設定し、テスト終了時に元に戻す
+func setenv(t *testing.T, envs map[string]string) {
+	for env, value := range envs {
+		before, ok := os.LookupEnv(env)
+		if err := os.Setenv(env, value); err != nil {
+			t.Fatal(err)
+		}
+		env := env
+		t.Cleanup(func(){
+			if ok {
+				os.Setenv(env, before)
+			} else {
+				os.Unsetenv(env)
+			}
+		})
+	}
+}
+
+// 設定に関係する環境変数を空にする
+func clearenv(t *testing.T) {
+	envs := map[string]string{FileEnv: ""}
+	for _, s := range (&Config{}).settings() {
+		envs[s.env] = ""
+	}
+	setenv(t, envs)
+}
+
+func writeConfig(t *testing.T, text string) string {
+	file := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(file, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("env", func(t *testing.T) {
+		clearenv(t)
+		setenv(t, map[string]string{
+			IncomeTypeEnv: "github",
+			WebHookUrlEnv: "https://example.com",
+			"SLACK_WEBHOOK_URL_AUDIT": "https://example.com/audit",
+			LocalEnv: "1",
+			AuditRouteEnv: "Audit",
+			"LOCALE_AUDIT": "en",
+			StarMilestonesEnv: "10, 20",
+			MentionsEnv: "Codertocat=U0123, octocat@example.com=U0456",
+			PackageFilterEnv: "hello-world, hello-*",
+			FilterRulesEnv: "exclude sender.type=Bot;\nexclude event=issues",
+			ReloadIntervalEnv: "60",
+			ReloadRouteEnv: "admin",
+			"SLACK_WEBHOOK_URL_ADMIN": "https://example.com/admin",
+		})
+		c, err := Load()
+		assert.Nil(err)
+		assert.True(c.Local)
+		assert.Equal(c.Slack.Routes, map[string]string{"audit": "https://example.com/audit", "admin": "https://example.com/admin"})
+		assert.Equal(c.Locale.Routes, map[string]string{"audit": "en"})
+		assert.Equal(c.Message.StarMilestones, []int{10, 20})
+		assert.Equal(c.Message.Mentions, map[string]string{"Codertocat": "U0123", "octocat@example.com": "U0456"})
+		assert.Equal(c.Message.PackageFilter, []string{"hello-world", "hello-*"})
+		assert.Equal(c.Filter.Rules, []string{"exclude sender.type=Bot", "exclude event=issues"})
+		assert.Equal(c.Reload, Reload{Interval: 60, Route: "admin"})
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		clearenv(t)
+		setenv(t, map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com"})
+		c, err := Load()
+		assert.Nil(err)
+		assert.False(c.Local)
+		assert.Equal(c.Message.StarMilestones, DefaultStarMilestones)
+		assert.Nil(c.Filter.Rules)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		clearenv(t)
+		setenv(t, map[string]string{
+			FileEnv: "./testdata/config.yml",
+			"TEST_SLACK_WEBHOOK_URL": "https://example.com",
+			"TEST_GITHUB_TOKEN": "xxx",
+			"TEST_STAR_BATCH_SIZE": "10",
+		})
+		c, err := Load()
+		assert.Nil(err)
+		assert.Equal(c.Income.Type, GitHubType)
+		assert.Equal(c.Slack, Slack{WebHookUrl: "https://example.com", Routes: map[string]string{"audit": "https://example.com/audit", "team": "https://example.com/team"}})
+		assert.Equal(c.GitHub.Token, "xxx")
+		assert.Equal(c.Routes.Audit, "audit")
+		assert.Equal(c.Locale, Locale{Default: "ja", Routes: map[string]string{"audit": "en"}})
+		assert.Equal(c.Message.StarBatchSize, 10)
+		assert.Equal(c.Message.DraftPullRequest, DraftSuppress)
+		assert.Equal(c.Message.Mentions, map[string]string{"Codertocat": "U0123"})
+		assert.Equal(c.Message.PackageFilter, []string{"hello-*"})
+		assert.Equal(len(c.Filter.Rules), 2)
+		assert.Equal(c.QuietHours.Routes[DefaultDestination], QuietWindow{
+			Timezone: "Asia/Tokyo",
+			Start: "22:00",
+			End: "08:00",
+			Weekdays: []string{"sat", "sun"},
+			Holidays: []string{"2026-01-01"},
+		})
+		assert.Equal(c.QuietHours.Urgent, []string{"include event=deployment_status"})
+		assert.Equal(c.Digest.Routes, map[string]DigestSchedule{"team": {Schedule: "0 9 * * mon-fri", Timezone: "Asia/Tokyo"}})
+	})
+
+	t.Run("env overrides file", func(t *testing.T) {
+		clearenv(t)
+		setenv(t, map[string]string{
+			FileEnv: "./testdata/config.yml",
+			"TEST_SLACK_WEBHOOK_URL": "https://example.com",
+			"TEST_GITHUB_TOKEN": "",
+			"TEST_STAR_BATCH_SIZE": "10",
+			DraftPullRequestEnv: DraftShow,
+		})
+		c, err := Load()
+		assert.Nil(err)
+		assert.Equal(c.GitHub.Token, "")
+		assert.Equal(c.Message.DraftPullRequest, DraftShow)
+	})
+
+	t.Run("flags", func(t *testing.T) {
+		file := writeConfig(t, "local: true\nreload:\n  announce: true\nmessage:\n  render_unhandled_events: true\nfilter:\n  dry_run: true\n")
+		for value, expected := range map[string]bool{"false": false, "0": false, "true": true, "1": true} {
+			clearenv(t)
+			setenv(t, map[string]string{
+				FileEnv: file,
+				IncomeTypeEnv: "github",
+				WebHookUrlEnv: "https://example.com",
+				LocalEnv: value,
+				ReloadAnnounceEnv: value,
+				RenderUnhandledEnv: value,
+				FilterDryRunEnv: value,
+			})
+			c, err := Load()
+			assert.Nil(err, value)
+			assert.Equal(c.Local, expected, value)
+			assert.Equal(c.Reload.Announce, expected, value)
+			assert.Equal(c.Message.RenderUnhandledEvents, expected, value)
+			assert.Equal(c.Filter.DryRun, expected, value)
+		}
+
+		// 設定されていない場合は設定ファイルの値
+		clearenv(t)
+		setenv(t, map[string]string{FileEnv: file, IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com"})
+		c, err := Load()
+		assert.Nil(err)
+		assert.True(c.Filter.DryRun)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			envs map[string]string
+			file string
+			err string
+		}{
+			{
+				envs: map[string]string{},
+				err: `Invalid income.type (INCOME_TYPE): "" is not supported (github)`,
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github"},
+				err: "Invalid slack.webhook_url (SLACK_WEBHOOK_URL): required",
+			},
+			{
+				envs: map[string]string{StarBatchSizeEnv: "xxx"},
+				err: `Invalid message.star_batch_size (STAR_BATCH_SIZE): "xxx" is not a number`,
+			},
+			{
+				envs: map[string]string{LocalEnv: "yes"},
+				err: `Invalid local (LOCAL): "yes" is not a boolean`,
+			},
+			{
+				envs: map[string]string{FilterDryRunEnv: "off"},
+				err: `Invalid filter.dry_run (FILTER_DRY_RUN): "off" is not a boolean`,
+			},
+			{
+				envs: map[string]string{RenderUnhandledEnv: "yes"},
+				err: `Invalid message.render_unhandled_events (RENDER_UNHANDLED_EVENTS): "yes" is not a boolean`,
+			},
+			{
+				envs: map[string]string{MentionsEnv: "Codertocat"},
+				err: `Invalid message.mentions (SLACK_MENTIONS): "Codertocat" is not <key>=<value>`,
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com", PackageFilterEnv: "hello-["},
+				err: `Invalid message.package_filter (PACKAGE_FILTER): "hello-[" is not a valid pattern`,
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com", DraftPullRequestEnv: "hide"},
+				err: `Invalid message.draft_pull_request (DRAFT_PULL_REQUEST): "hide" is not one of mute, suppress, show`,
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com", AuditRouteEnv: "Audit"},
+				err: "Invalid routes.audit (AUDIT_ROUTE): SLACK_WEBHOOK_URL_AUDIT is not set",
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com", SponsorRouteEnv: "sponsor"},
+				err: "Invalid routes.sponsor (SPONSOR_ROUTE): SLACK_WEBHOOK_URL_SPONSOR is not set",
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com", ReloadRouteEnv: "admin"},
+				err: "Invalid reload.route (CONFIG_RELOAD_ROUTE): SLACK_WEBHOOK_URL_ADMIN is not set",
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com"},
+				file: "quiet_hours:\n  routes:\n    Team:\n      start: \"22:00\"\n      end: \"08:00\"\n",
+				err: "Invalid quiet_hours.routes.team (CONFIG_FILE): SLACK_WEBHOOK_URL_TEAM is not set",
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com"},
+				file: "digest:\n  routes:\n    Team:\n      schedule: \"@daily\"\n",
+				err: "Invalid digest.routes.team (CONFIG_FILE): SLACK_WEBHOOK_URL_TEAM is not set",
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com", AuditRouteEnv: "audit", "LOCALE_AUDIT": "fr"},
+				err: `Invalid locale.routes.audit (LOCALE_AUDIT): "fr" is not one of en, ja`,
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com", ReloadIntervalEnv: "-1"},
+				err: "Invalid reload.interval (CONFIG_RELOAD_INTERVAL): must not be negative",
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com", RepoConfigPathEnv: ".github/ggnb.yml"},
+				err: "Invalid repo.path (REPO_CONFIG_PATH): GITHUB_TOKEN is required",
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com", RepoConfigAllowEnv: "route,webhook_url"},
+				err: `Invalid repo.allow (REPO_CONFIG_ALLOW): "webhook_url" is not one of route, filter, mentions`,
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com"},
+				file: "quiet_hours:\n  routes:\n    default:\n      timezone: Asia/Nowhere\n",
+				err: `Invalid quiet_hours.routes.default (CONFIG_FILE): "Asia/Nowhere" is not a valid timezone`,
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com"},
+				file: "quiet_hours:\n  routes:\n    default:\n      start: \"22:00\"\n",
+				err: "Invalid quiet_hours.routes.default (CONFIG_FILE): start and end are required together",
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com"},
+				file: "quiet_hours:\n  routes:\n    Audit:\n      start: \"22:00\"\n      end: \"25:00\"\n",
+				err: `Invalid quiet_hours.routes.audit (CONFIG_FILE): "25:00" is not HH:MM`,
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com"},
+				file: "quiet_hours:\n  routes:\n    default:\n      weekdays: [saturday]\n      holidays: [2026/01/01]\n",
+				err: `Invalid quiet_hours.routes.default (CONFIG_FILE): "saturday" is not one of sun, mon, tue, wed, thu, fri, sat`,
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com"},
+				file: "quiet_hours:\n  routes:\n    default:\n      holidays: [2026/01/01]\n",
+				err: `Invalid quiet_hours.routes.default (CONFIG_FILE): "2026/01/01" is not 2006-01-02`,
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com"},
+				file: "digest:\n  routes:\n    Team:\n      schedule: \"0 25 * * *\"\n",
+				err: "Invalid digest.routes.team.schedule (CONFIG_FILE): ",
+			},
+			{
+				envs: map[string]string{IncomeTypeEnv: "github", WebHookUrlEnv: "https://example.com"},
+				file: "digest:\n  routes:\n    default:\n      schedule: \"@daily\"\n      timezone: Asia/Nowhere\n",
+				err: `Invalid digest.routes.default.timezone (CONFIG_FILE): "Asia/Nowhere" is not a valid timezone`,
+			},
+			{
+				file: "income:\n  type: github\n  kind: github\n",
+				err: "line 3: field kind not found in type config.Income",
+			},
+			{
+				file: "message:\n  star_batch_size: many\n",
+				err: "cannot unmarshal !!str `many` into int",
+			},
+			{
+				file: "slack:\n  webhook_url: ${TEST_UNDEFINED_VARIABLE}\n",
+				err: "line 2: TEST_UNDEFINED_VARIABLE is not set",
+			},
+		} {
+			clearenv(t)
+			setenv(t, tc.envs)
+			if tc.file != "" {
+				setenv(t, map[string]string{FileEnv: writeConfig(t, tc.file)})
+			}
+			_, err := Load()
+			if assert.NotNil(err, tc.err) {
+				assert.Contains(err.Error(), tc.err)
+			}
+		}
+	})
+}
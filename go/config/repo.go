@@ -0,0 +1,82 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/SongCastle/ggnb/income/filter"
+	"gopkg.in/yaml.v3"
+)
+
+// レポジトリの設定ファイルで上書きできる項目
+const (
+	RepoRoute = "route"
+	RepoFilter = "filter"
+	RepoMentions = "mentions"
+)
+
+var repoKeys = map[string]bool{
+	RepoRoute: true,
+	RepoFilter: true,
+	RepoMentions: true,
+}
+
+// レポジトリの設定ファイル (.github/ggnb.yml 等)
+type RepoFile struct {
+	// 通知先の route (slack.routes に設定されているもの)
+	Route string `yaml:"route"`
+	Filter RepoFileFilter `yaml:"filter"`
+	Mentions map[string]string `yaml:"mentions"`
+}
+
+type RepoFileFilter struct {
+	// filter.rules より先に評価する
+	Rules []string `yaml:"rules"`
+}
+
+// レポジトリの所有者が編集するため、${VAR} は置き換えない
+func ParseRepoFile(text []byte) (*RepoFile, error) {
+	f := &RepoFile{}
+	d := yaml.NewDecoder(bytes.NewReader(text))
+	d.KnownFields(true)
+	if err := d.Decode(f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	f.Route = strings.ToLower(f.Route)
+	for _, rule := range f.Filter.Rules {
+		if _, err := filter.ParseRule(rule); err != nil {
+			return nil, errors.New(fmt.Sprintf("%q is not a valid rule", rule))
+		}
+	}
+	for login, id := range f.Mentions {
+		if login == "" || id == "" {
+			return nil, errors.New("login and Slack user ID are required in mentions")
+		}
+	}
+	return f, nil
+}
+
+// allow に含まれない項目を取り除き、取り除いた項目を返す
+func (f *RepoFile) Restrict(allow []string) []string {
+	allowed := map[string]bool{}
+	for _, key := range allow {
+		allowed[key] = true
+	}
+	var dropped []string
+	if f.Route != "" && !allowed[RepoRoute] {
+		f.Route = ""
+		dropped = append(dropped, RepoRoute)
+	}
+	if len(f.Filter.Rules) > 0 && !allowed[RepoFilter] {
+		f.Filter.Rules = nil
+		dropped = append(dropped, RepoFilter)
+	}
+	if len(f.Mentions) > 0 && !allowed[RepoMentions] {
+		f.Mentions = nil
+		dropped = append(dropped, RepoMentions)
+	}
+	return dropped
+}
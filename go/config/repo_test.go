@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRepoFile(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	t.Run("ok", func(t *testing.T) {
+		f, err := ParseRepoFile([]byte("route: Frontend\nfilter:\n  rules:\n    - exclude sender.type=Bot\nmentions:\n  Codertocat: U0123\n"))
+		assert.Nil(err)
+		assert.Equal(f, &RepoFile{
+			Route: "frontend",
+			Filter: RepoFileFilter{Rules: []string{"exclude sender.type=Bot"}},
+			Mentions: map[string]string{"Codertocat": "U0123"},
+		})
+	})
+
+	// 環境変数は参照できない
+	t.Run("variable", func(t *testing.T) {
+		f, err := ParseRepoFile([]byte("route: ${SLACK_WEBHOOK_URL}\n"))
+		assert.Nil(err)
+		assert.Equal(f.Route, "${slack_webhook_url}")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		f, err := ParseRepoFile([]byte(""))
+		assert.Nil(err)
+		assert.Equal(f, &RepoFile{})
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for text, expected := range map[string]string{
+			"channel: frontend\n": "field channel not found",
+			"filter:\n  rules:\n    - drop sender.type=Bot\n": `"drop sender.type=Bot" is not a valid rule`,
+			"mentions:\n  Codertocat: \"\"\n": "login and Slack user ID are required in mentions",
+		} {
+			_, err := ParseRepoFile([]byte(text))
+			if assert.NotNil(err, text) {
+				assert.Contains(err.Error(), expected)
+			}
+		}
+	})
+}
+
+func TestRepoFileRestrict(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	f := &RepoFile{
+		Route: "frontend",
+		Filter: RepoFileFilter{Rules: []string{"exclude sender.type=Bot"}},
+		Mentions: map[string]string{"Codertocat": "U0123"},
+	}
+	dropped := f.Restrict([]string{RepoMentions})
+	assert.Equal(dropped, []string{RepoRoute, RepoFilter})
+	assert.Equal(f, &RepoFile{Mentions: map[string]string{"Codertocat": "U0123"}})
+}
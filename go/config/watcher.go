@@ -0,0 +1,107 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type AbstractWatcher interface {
+	// 変更されていない場合は nil を返す
+	Check() (*Config, error)
+	// Check で読み込んだ設定を反映した場合に呼び出す (呼び出すまでは変更されたものとして扱う)
+	Accept()
+}
+
+// CONFIG_FILE もしくは reload.interval が設定されていない場合は nil を返す
+func NewWatcher(cfg *Config) AbstractWatcher {
+	if cfg.File == "" || cfg.Reload.Interval <= 0 {
+		return nil
+	}
+	w := &Watcher{
+		file: cfg.File,
+		templateDir: cfg.Message.TemplateDir,
+		interval: time.Duration(cfg.Reload.Interval) * time.Second,
+		now: time.Now,
+	}
+	w.checked = w.now()
+	w.stamp, _ = fileStamp(w.file, w.templateDir)
+	return w
+}
+
+// 常駐するプロセスはなく、Lambda は呼び出しの間プロセスが停止するため、バックグラウンドで監視せず
+// Check が呼ばれた際 (リクエストごと) に interval ごとに設定ファイル・テンプレートの更新日時・サイズを確認する
+type Watcher struct {
+	file string
+	// message.template_dir (設定ファイルとともに読み込み直す)
+	templateDir string
+	interval time.Duration
+	now func() time.Time
+	checked time.Time
+	// 設定ファイル・テンプレートの更新日時・サイズ (設定ファイルがない場合は空)
+	stamp string
+	// Check で読み込み、まだ Accept されていないもの
+	pendingStamp string
+	pendingTemplateDir string
+}
+
+// 読み込みに失敗した場合はエラーを返す (ファイルが変更されていなくても interval ごとに読み込み直す)
+func (w *Watcher) Check() (*Config, error) {
+	now := w.now()
+	if now.Sub(w.checked) < w.interval {
+		return nil, nil
+	}
+	w.checked = now
+	stamp, err := fileStamp(w.file, w.templateDir)
+	if err != nil {
+		if w.stamp == "" {
+			return nil, nil
+		}
+		return nil, errors.New(fmt.Sprintf("Invalid %s: %v", FileEnv, err))
+	}
+	if stamp == w.stamp {
+		return nil, nil
+	}
+	c, err := load(w.file)
+	if err != nil {
+		return nil, err
+	}
+	// message.template_dir が変更された場合は、変更後のディレクトリを確認する
+	if dir := c.Message.TemplateDir; dir != w.templateDir {
+		if stamp, err = fileStamp(w.file, dir); err != nil {
+			return nil, errors.New(fmt.Sprintf("Invalid %s: %v", FileEnv, err))
+		}
+	}
+	w.pendingStamp, w.pendingTemplateDir = stamp, c.Message.TemplateDir
+	return c, nil
+}
+
+func (w *Watcher) Accept() {
+	w.stamp, w.templateDir = w.pendingStamp, w.pendingTemplateDir
+}
+
+// <パス> <更新日時> <サイズ> を設定ファイル・テンプレートディレクトリのファイルごとに並べる
+// (ディレクトリが読み込めない場合はファイルがないものとして扱う)
+func fileStamp(file, templateDir string) (string, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s %d %d\n", file, info.ModTime().UnixNano(), info.Size()))
+	if templateDir == "" {
+		return b.String(), nil
+	}
+	entries, _ := os.ReadDir(templateDir)
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || info.IsDir() {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("%s %d %d\n", filepath.Join(templateDir, e.Name()), info.ModTime().UnixNano(), info.Size()))
+	}
+	return b.String(), nil
+}
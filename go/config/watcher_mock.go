@@ -0,0 +1,18 @@
+package config
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type MockedWatcher struct {
+	mock.Mock
+}
+
+func (m *MockedWatcher) Check() (*Config, error) {
+	args := m.Called()
+	return args[0].(*Config), args.Error(1)
+}
+
+func (m *MockedWatcher) Accept() {
+	m.Called()
+}
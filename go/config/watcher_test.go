@@ -0,0 +1,148 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWatcher(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	assert.Nil(NewWatcher(&Config{}))
+	assert.Nil(NewWatcher(&Config{File: "config.yml"}))
+	assert.IsType(NewWatcher(&Config{File: "config.yml", Reload: Reload{Interval: 10}}), &Watcher{})
+}
+
+func TestWatcherCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	clearenv(t)
+	file := writeConfig(t, "income:\n  type: github\nslack:\n  webhook_url: https://example.com\n")
+	now := time.Now()
+	w := NewWatcher(&Config{File: file, Reload: Reload{Interval: 10}}).(*Watcher)
+	w.now = func() time.Time { return now }
+	w.checked = now
+
+	// ファイルの更新日時を進める
+	write := func(text string) {
+		if err := os.WriteFile(file, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		w.stamp += "(outdated)"
+	}
+
+	t.Run("not changed", func(t *testing.T) {
+		now = now.Add(10 * time.Second)
+		c, err := w.Check()
+		assert.Nil(err)
+		assert.Nil(c)
+	})
+
+	t.Run("before interval", func(t *testing.T) {
+		write("income:\n  type: github\nslack:\n  webhook_url: https://example.com/new\n")
+		now = now.Add(5 * time.Second)
+		c, err := w.Check()
+		assert.Nil(err)
+		assert.Nil(c)
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		now = now.Add(5 * time.Second)
+		c, err := w.Check()
+		assert.Nil(err)
+		assert.Equal(c.File, file)
+		assert.Equal(c.Slack.WebHookUrl, "https://example.com/new")
+
+		// Accept するまでは変更されたものとして扱う
+		now = now.Add(10 * time.Second)
+		c, err = w.Check()
+		assert.Nil(err)
+		assert.NotNil(c)
+		w.Accept()
+
+		now = now.Add(10 * time.Second)
+		c, err = w.Check()
+		assert.Nil(err)
+		assert.Nil(c)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		write("income:\n  type: gitlab\n")
+		now = now.Add(10 * time.Second)
+		c, err := w.Check()
+		assert.EqualError(err, `Invalid income.type (INCOME_TYPE): "gitlab" is not supported (github)`)
+		assert.Nil(c)
+
+		// 変更されていなくても読み込み直す
+		now = now.Add(10 * time.Second)
+		_, err = w.Check()
+		assert.NotNil(err)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		if err := os.Remove(file); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(10 * time.Second)
+		_, err := w.Check()
+		assert.NotNil(err)
+
+		// 元に戻した場合は読み込み直す
+		write("income:\n  type: github\nslack:\n  webhook_url: https://example.com\n")
+		now = now.Add(10 * time.Second)
+		c, err := w.Check()
+		assert.Nil(err)
+		assert.Equal(c.Slack.WebHookUrl, "https://example.com")
+	})
+}
+
+func TestWatcherCheckTemplates(t *testing.T) {
+	assert := assert.New(t)
+
+	clearenv(t)
+	dir := t.TempDir()
+	template := filepath.Join(dir, "issues.tmpl")
+	if err := os.WriteFile(template, []byte("{{ .action }}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file := writeConfig(t, "income:\n  type: github\nslack:\n  webhook_url: https://example.com\nmessage:\n  template_dir: "+dir+"\n")
+	now := time.Now()
+	w := NewWatcher(&Config{File: file, Message: Message{TemplateDir: dir}, Reload: Reload{Interval: 10}}).(*Watcher)
+	w.now = func() time.Time { return now }
+
+	now = now.Add(10 * time.Second)
+	c, err := w.Check()
+	assert.Nil(err)
+	assert.Nil(c)
+
+	// 設定ファイルが変更されていなくても、テンプレートが変更された場合は読み込み直す
+	if err := os.WriteFile(template, []byte("{{ .action }} by {{ .sender.login }}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(10 * time.Second)
+	c, err = w.Check()
+	assert.Nil(err)
+	if assert.NotNil(c) {
+		assert.Equal(c.Message.TemplateDir, dir)
+	}
+	w.Accept()
+
+	now = now.Add(10 * time.Second)
+	c, err = w.Check()
+	assert.Nil(err)
+	assert.Nil(c)
+
+	// テンプレートを追加した場合
+	if err := os.WriteFile(filepath.Join(dir, "push.tmpl"), []byte("{{ .ref }}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(10 * time.Second)
+	c, err = w.Check()
+	assert.Nil(err)
+	assert.NotNil(c)
+}
@@ -0,0 +1,65 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	for _, spec := range []string{
+		"0 9 * * 1",
+		"*/15 9-18 * * mon-fri",
+		"0 0 1,15 jan-jun *",
+		"30 8 * * 7",
+		"@daily",
+	} {
+		_, err := Parse(spec)
+		assert.Nil(err, spec)
+	}
+
+	for spec, expected := range map[string]string{
+		"0 9 * *": `"0 9 * *" must have 5 fields`,
+		"60 9 * * *": `"60" is out of range (0-59)`,
+		"0 9 * * 1-8": `"1-8" is out of range (0-7)`,
+		"0 9 * * foo": `"foo" is not a number`,
+		"*/0 9 * * *": `"*/0" is not a valid step`,
+		"0 18-9 * * *": `"18-9" is out of range (0-23)`,
+	} {
+		_, err := Parse(spec)
+		assert.EqualError(err, expected, spec)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	jst := time.FixedZone("JST", 9*60*60)
+	// 月曜日
+	base := time.Date(2026, 10, 19, 10, 30, 0, 0, jst)
+
+	for spec, expected := range map[string]time.Time{
+		"0 9 * * *": time.Date(2026, 10, 20, 9, 0, 0, 0, jst),
+		"0 9 * * mon": time.Date(2026, 10, 26, 9, 0, 0, 0, jst),
+		"*/15 * * * *": time.Date(2026, 10, 19, 10, 45, 0, 0, jst),
+		"0 12 1 * *": time.Date(2026, 11, 1, 12, 0, 0, 0, jst),
+		"30 10 * * *": time.Date(2026, 10, 20, 10, 30, 0, 0, jst),
+		// 日・曜日の両方を指定した場合はいずれか
+		"0 9 1 * fri": time.Date(2026, 10, 23, 9, 0, 0, 0, jst),
+		"0 0 29 2 *": time.Date(2028, 2, 29, 0, 0, 0, 0, jst),
+		"@weekly": time.Date(2026, 10, 25, 0, 0, 0, 0, jst),
+	} {
+		s, err := Parse(spec)
+		assert.Nil(err, spec)
+		assert.Equal(s.Next(base), expected, spec)
+	}
+
+	s, err := Parse("0 0 30 2 *")
+	assert.Nil(err)
+	assert.True(s.Next(base).IsZero())
+}
@@ -7,4 +7,5 @@ require (
 	github.com/google/go-github/v38 v38.1.0
 	github.com/jarcoal/httpmock v1.0.8
 	github.com/stretchr/testify v1.6.1
+	gopkg.in/yaml.v3 v3.0.0-20200615113413-eeeca48fe776
 )
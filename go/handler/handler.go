@@ -1,35 +1,24 @@
 package handler
 
 import (
-	"os"
-
+	"github.com/SongCastle/ggnb/config"
 	"github.com/SongCastle/ggnb/income"
-	"github.com/SongCastle/ggnb/income/message"
 	"github.com/SongCastle/ggnb/outcome"
-	"github.com/SongCastle/ggnb/outcome/client"
 )
 
-const LOCAL = "LOCAL"
-
 type abstractHandler interface {
 	Init(income.AbstractManager, outcome.AbstractManager)
+	SetReloader(*Reloader)
 	Start()
 }
 
-func New(am message.AbstractMessage, ac client.AbstractClient) abstractHandler {
+func New(cfg *config.Config, in income.AbstractManager, out outcome.AbstractManager) abstractHandler {
 	var h abstractHandler
-	if onLambda() {
+	if !cfg.Local {
 		h = &lambdaHandler{}
 	} else {
 		h = &localHandler{}
 	}
-	// Create Manager
-	in := income.NewManager(am)
-	out := outcome.NewManager(ac)
 	h.Init(in, out)
 	return h
 }
-
-func onLambda() bool {
-	return os.Getenv(LOCAL) == ""
-}
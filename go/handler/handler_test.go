@@ -2,45 +2,38 @@ package handler
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
-	"os"
 	"testing"
+	"time"
 
+	"github.com/SongCastle/ggnb/config"
 	"github.com/SongCastle/ggnb/income"
+	"github.com/SongCastle/ggnb/income/builder"
 	"github.com/SongCastle/ggnb/income/message"
 	"github.com/SongCastle/ggnb/outcome"
 	"github.com/SongCastle/ggnb/outcome/client"
+	"github.com/SongCastle/ggnb/outcome/digest"
+	"github.com/SongCastle/ggnb/outcome/schedule"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestHandlerNew(t *testing.T) {
 	assert := assert.New(t)
-	beforeLocal := os.Getenv(LOCAL)
 
-	m := &message.MockedMessage{}
-	c := &client.MockedClient{}
+	in := &income.MockedIncomeManager{}
+	out := &outcome.MockedOutcomeManager{}
 
 	t.Run("local", func(t *testing.T) {
-		if err := os.Setenv(LOCAL, "1"); err != nil {
-			t.Fatal(err)
-		}
-		h := New(m, c)
+		h := New(&config.Config{Local: true}, in, out)
 		assert.IsType(h, &localHandler{})
 	})
 
 	t.Run("lambda", func(t *testing.T) {
-		if err := os.Unsetenv(LOCAL); err != nil {
-			t.Fatal(err)
-		}
-		h := New(m, c)
+		h := New(&config.Config{}, in, out)
 		assert.IsType(h, &lambdaHandler{})
 	})
-
-	t.Cleanup(func(){
-		if err := os.Setenv(LOCAL, beforeLocal); err != nil {
-			t.Fatal(err)
-		}
-	})
 }
 
 func TestLocalHandlerInit(t *testing.T) {
@@ -103,3 +96,129 @@ func TestLambdaHandlerInit(t *testing.T) {
 func TestLambdaHandlerSrart(t *testing.T) {
 	// TODO: lambda 依存のテストケースについて
 }
+
+func TestLambdaHandlerHandle(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("webhook", func(t *testing.T) {
+		msg := bytes.NewBufferString(`{"body": "test"}`)
+		entry := &builder.DigestEntry{Event: "issues", Action: "opened"}
+		in := &income.MockedIncomeManager{}
+		in.On("BuildMessage", map[string]string{"X-GitHub-Event": "issues"}, mock.Anything).Return(msg, nil)
+		in.On("Route").Return("team")
+		in.On("Urgent").Return(false)
+		in.On("Digest").Return(entry)
+
+		out := &outcome.MockedOutcomeManager{}
+		out.On("Deliver", "team", msg, false, entry).Return(nil)
+
+		h := &lambdaHandler{In: in, Out: out}
+		res, err := h.handle(json.RawMessage(`{"headers":{"X-GitHub-Event":"issues"},"body":"{}"}`))
+		assert.Nil(err)
+		assert.Equal(res.StatusCode, 200)
+		out.AssertExpectations(t)
+	})
+
+	// 通知を控える時間帯でも、本番環境へのデプロイの失敗は組み込みの builder で通知する
+	t.Run("urgent during quiet hours", func(t *testing.T) {
+		cfg := &config.Config{Income: config.Income{Type: config.GitHubType}, Store: config.Store{Dir: t.TempDir()}}
+		m, err := message.NewMessage(cfg)
+		assert.Nil(err)
+
+		c := &client.MockedClient{}
+		c.On("PostTo", "", mock.AnythingOfType("*bytes.Buffer")).Return([]byte("ok"), nil)
+		s := &schedule.MockedSchedule{}
+		s.On("Release", mock.Anything).Return(nil, nil)
+		s.On("Quiet", "", mock.Anything).Return(true)
+
+		h := &lambdaHandler{In: income.NewManager(m), Out: outcome.NewManager(c, s, nil)}
+		body := `{"action":"created","deployment":{"ref":"main"},"deployment_status":{"state":"failure","environment":"production"}}`
+		payload, err := json.Marshal(map[string]interface{}{
+			"headers": map[string]string{"X-GitHub-Event": "deployment_status"},
+			"body": body,
+		})
+		assert.Nil(err)
+		res, err := h.handle(payload)
+		assert.Nil(err)
+		assert.Equal(res.StatusCode, 200)
+		c.AssertNumberOfCalls(t, "PostTo", 1)
+		assert.Contains(c.Calls[0].Arguments.Get(1).(*bytes.Buffer).String(), "デプロイに失敗しました")
+		s.AssertNotCalled(t, "Queue", mock.Anything, mock.Anything)
+
+		// 完了していない場合は通知も溜めもしない
+		body = `{"action":"created","deployment_status":{"state":"in_progress","environment":"production"}}`
+		payload, err = json.Marshal(map[string]interface{}{
+			"headers": map[string]string{"X-GitHub-Event": "deployment_status"},
+			"body": body,
+		})
+		assert.Nil(err)
+		res, err = h.handle(payload)
+		assert.Nil(err)
+		assert.Equal(res.StatusCode, 200)
+		c.AssertNumberOfCalls(t, "PostTo", 1)
+		s.AssertNotCalled(t, "Queue", mock.Anything, mock.Anything)
+	})
+
+	// 通知しない Event (release) もダイジェストには含める
+	t.Run("release recorded in digest", func(t *testing.T) {
+		cfg := &config.Config{
+			Income: config.Income{Type: config.GitHubType},
+			Store: config.Store{Dir: t.TempDir()},
+			Digest: config.Digest{Routes: map[string]config.DigestSchedule{config.DefaultDestination: {Schedule: "@daily"}}},
+		}
+		m, err := message.NewMessage(cfg)
+		assert.Nil(err)
+		d, err := digest.NewDigest(cfg)
+		assert.Nil(err)
+
+		c := &client.MockedClient{}
+		h := &lambdaHandler{In: income.NewManager(m), Out: outcome.NewManager(c, nil, d)}
+		body := `{"action":"published","release":{"tag_name":"v1.0.0","html_url":"https://github.com/Codertocat/Hello-World/releases/tag/v1.0.0"},"sender":{"login":"Codertocat","type":"User"}}`
+		payload, err := json.Marshal(map[string]interface{}{
+			"headers": map[string]string{"X-GitHub-Event": "release"},
+			"body": body,
+		})
+		assert.Nil(err)
+		res, err := h.handle(payload)
+		assert.Nil(err)
+		assert.Equal(res.StatusCode, 200)
+		c.AssertNotCalled(t, "PostTo", mock.Anything, mock.Anything)
+
+		summaries, err := d.Release(time.Now().Add(48 * time.Hour))
+		assert.Nil(err)
+		if assert.Len(summaries, 1) {
+			s := summaries[0]
+			buf, err := builder.BuildDigest(s.Route, s.From, s.To, s.Entries)
+			assert.Nil(err)
+			assert.Contains(buf.String(), `{"title":"リリース","value":"\u003chttps://github.com/Codertocat/Hello-World/releases/tag/v1.0.0|v1.0.0\u003e (Codertocat)","short":false}`)
+		}
+	})
+
+	t.Run("scheduled", func(t *testing.T) {
+		in := &income.MockedIncomeManager{}
+		out := &outcome.MockedOutcomeManager{}
+		out.On("Flush").Return(nil)
+
+		h := &lambdaHandler{In: in, Out: out}
+		res, err := h.handle(json.RawMessage(`{"source":"aws.events","detail-type":"Scheduled Event","detail":{}}`))
+		assert.Nil(err)
+		assert.Equal(res.StatusCode, 200)
+		out.AssertExpectations(t)
+		in.AssertNotCalled(t, "BuildMessage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var b *bytes.Buffer
+		err := errors.New("mocked")
+		in := &income.MockedIncomeManager{}
+		in.On("BuildMessage", mock.Anything, mock.Anything).Return(b, err)
+
+		out := &outcome.MockedOutcomeManager{}
+		out.On("ReportErrorIf", err).Return(err)
+
+		h := &lambdaHandler{In: in, Out: out}
+		res, _ := h.handle(json.RawMessage(`{"body":"{}"}`))
+		assert.Equal(res.StatusCode, 400)
+		assert.Equal(res.Body, "mocked")
+	})
+}
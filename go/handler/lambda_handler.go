@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"encoding/json"
+
 	"github.com/SongCastle/ggnb/income"
 	"github.com/SongCastle/ggnb/outcome"
 
@@ -11,6 +13,7 @@ import (
 type lambdaHandler struct {
 	In income.AbstractManager
 	Out outcome.AbstractManager
+	reloader *Reloader
 }
 
 func (lh *lambdaHandler) Init(in income.AbstractManager, out outcome.AbstractManager) {
@@ -18,20 +21,53 @@ func (lh *lambdaHandler) Init(in income.AbstractManager, out outcome.AbstractMan
 	lh.Out = out
 }
 
+func (lh *lambdaHandler) SetReloader(r *Reloader) {
+	lh.reloader = r
+}
+
+// 設定ファイルが変更されている場合は In・Out をまとめて差し替える
+// (Lambda は 1 つのプロセスで同時に 1 つのリクエストのみ処理する)
+func (lh *lambdaHandler) reload() {
+	if in, out := lh.reloader.Reload(lh.Out); in != nil {
+		lh.In, lh.Out = in, out
+	}
+}
+
+// EventBridge (CloudWatch Events) の定期実行
+const (
+	scheduledSource = "aws.events"
+	scheduledDetailType = "Scheduled Event"
+)
+
 func (lh *lambdaHandler) Start() {
-	lambda.Start(
-		func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-			msg, err := lh.In.BuildMessage(request.Headers, &request.Body)
-			if err == nil {
-				err = lh.Out.Send(msg)
-			}
-
-			body, statusCode := "ok", 200
-			if err != nil {
-				body, statusCode = err.Error(), 400
-				lh.Out.ReportErrorIf(err)
-			}
-			return events.APIGatewayProxyResponse{Body: body, StatusCode: statusCode}, nil
-		},
-	)
+	lambda.Start(lh.handle)
+}
+
+// API Gateway からの WebHook と、EventBridge からの定期実行を受け付ける
+func (lh *lambdaHandler) handle(payload json.RawMessage) (events.APIGatewayProxyResponse, error) {
+	lh.reload()
+	var scheduled events.CloudWatchEvent
+	if err := json.Unmarshal(payload, &scheduled); err == nil &&
+		scheduled.Source == scheduledSource && scheduled.DetailType == scheduledDetailType {
+		return lh.respond(lh.Out.Flush()), nil
+	}
+
+	var request events.APIGatewayProxyRequest
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return lh.respond(err), nil
+	}
+	msg, err := lh.In.BuildMessage(request.Headers, &request.Body)
+	if err == nil {
+		err = lh.Out.Deliver(lh.In.Route(), msg, lh.In.Urgent(), lh.In.Digest())
+	}
+	return lh.respond(err), nil
+}
+
+func (lh *lambdaHandler) respond(err error) events.APIGatewayProxyResponse {
+	body, statusCode := "ok", 200
+	if err != nil {
+		body, statusCode = err.Error(), 400
+		lh.Out.ReportErrorIf(err)
+	}
+	return events.APIGatewayProxyResponse{Body: body, StatusCode: statusCode}
 }
@@ -15,6 +15,10 @@ func (lh *localHandler) Init(in income.AbstractManager, out outcome.AbstractMana
 	lh.Out = out
 }
 
+// 常駐せず 1 度しか実行しないため、設定ファイルを読み込み直さない
+func (lh *localHandler) SetReloader(_ *Reloader) {
+}
+
 func (lh *localHandler) Start() {
 	msg, err := lh.In.BuildDummyMessage()
 	if err == nil {
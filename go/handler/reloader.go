@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/SongCastle/ggnb/config"
+	"github.com/SongCastle/ggnb/income"
+	"github.com/SongCastle/ggnb/income/builder"
+	"github.com/SongCastle/ggnb/outcome"
+)
+
+// 設定から income/outcome を作成する
+type Factory func(*config.Config) (income.AbstractManager, outcome.AbstractManager, error)
+
+// 設定ファイルが変更された場合に income/outcome を作り直す
+type Reloader struct {
+	cfg *config.Config
+	watcher config.AbstractWatcher
+	factory Factory
+	// 前回の読み込みのエラー (同じエラーは再度通知しない)
+	failed string
+}
+
+// 設定ファイルを監視しない場合は nil を返す
+func NewReloader(cfg *config.Config, factory Factory) *Reloader {
+	w := config.NewWatcher(cfg)
+	if w == nil {
+		return nil
+	}
+	return &Reloader{cfg: cfg, watcher: w, factory: factory}
+}
+
+// 変更されていない場合・読み込みに失敗した場合は nil を返す (以前の設定を使い続ける)
+// 失敗した場合は次の確認の際に読み込み直す
+func (r *Reloader) Reload(out outcome.AbstractManager) (income.AbstractManager, outcome.AbstractManager) {
+	if r == nil {
+		return nil, nil
+	}
+	cfg, err := r.watcher.Check()
+	if err == nil && cfg == nil {
+		return nil, nil
+	}
+	var in income.AbstractManager
+	var newOut outcome.AbstractManager
+	if err == nil {
+		in, newOut, err = r.factory(cfg)
+	}
+	if err != nil {
+		fmt.Printf("Config Reload Failed: %v\n", err)
+		if err.Error() != r.failed {
+			r.failed = err.Error()
+			r.announce(out, r.cfg, err)
+		}
+		return nil, nil
+	}
+	fmt.Printf("Config Reloaded: %s\n", cfg.File)
+	r.watcher.Accept()
+	r.failed = ""
+	r.cfg = cfg
+	r.announce(newOut, cfg, nil)
+	return in, newOut
+}
+
+func (r *Reloader) announce(out outcome.AbstractManager, cfg *config.Config, reloadErr error) {
+	if !cfg.Reload.Announce {
+		return
+	}
+	msg, err := builder.BuildConfigReload(cfg.Reload.Route, cfg.File, reloadErr)
+	if err == nil {
+		err = out.SendTo(cfg.Reload.Route, msg)
+	}
+	if err != nil {
+		fmt.Printf("Announce Failed: %v\n", err)
+	}
+}
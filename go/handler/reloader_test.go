@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SongCastle/ggnb/config"
+	"github.com/SongCastle/ggnb/income"
+	"github.com/SongCastle/ggnb/outcome"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewReloader(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	assert.Nil(NewReloader(&config.Config{}, nil))
+	assert.NotNil(NewReloader(&config.Config{File: "config.yml", Reload: config.Reload{Interval: 10}}, nil))
+}
+
+func TestReloaderReload(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	var nilConfig *config.Config
+	current := &config.Config{File: "config.yml", Reload: config.Reload{Announce: true, Route: "admin"}}
+	newOut := &outcome.MockedOutcomeManager{}
+	factory := func(_ *config.Config) (income.AbstractManager, outcome.AbstractManager, error) {
+		return &income.MockedIncomeManager{}, newOut, nil
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		var r *Reloader
+		in, out := r.Reload(&outcome.MockedOutcomeManager{})
+		assert.Nil(in)
+		assert.Nil(out)
+	})
+
+	t.Run("not changed", func(t *testing.T) {
+		w := &config.MockedWatcher{}
+		w.On("Check").Return(nilConfig, nil)
+
+		r := &Reloader{cfg: current, watcher: w, factory: factory}
+		in, out := r.Reload(&outcome.MockedOutcomeManager{})
+		assert.Nil(in)
+		assert.Nil(out)
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		w := &config.MockedWatcher{}
+		w.On("Check").Return(&config.Config{File: "config.yml"}, nil)
+		w.On("Accept").Return()
+
+		r := &Reloader{cfg: current, watcher: w, factory: factory}
+		in, out := r.Reload(&outcome.MockedOutcomeManager{})
+		assert.NotNil(in)
+		assert.Equal(out, newOut)
+		assert.Equal(r.cfg.Reload.Announce, false)
+		w.AssertCalled(t, "Accept")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		w := &config.MockedWatcher{}
+		w.On("Check").Return(nilConfig, errors.New("mocked"))
+		prev := &outcome.MockedOutcomeManager{}
+		prev.On("SendTo", "admin", mock.Anything).Return(nil)
+
+		r := &Reloader{cfg: current, watcher: w, factory: factory}
+		in, out := r.Reload(prev)
+		assert.Nil(in)
+		assert.Nil(out)
+		assert.Equal(r.cfg, current)
+		// 以前の設定の通知先に失敗を通知する
+		prev.AssertCalled(t, "SendTo", "admin", mock.Anything)
+
+		// 同じエラーは再度通知しない
+		r.Reload(prev)
+		prev.AssertNumberOfCalls(t, "SendTo", 1)
+	})
+
+	t.Run("factory error", func(t *testing.T) {
+		w := &config.MockedWatcher{}
+		w.On("Check").Return(&config.Config{File: "config.yml"}, nil)
+		prev := &outcome.MockedOutcomeManager{}
+		prev.On("SendTo", "admin", mock.Anything).Return(nil)
+
+		r := &Reloader{
+			cfg: current,
+			watcher: w,
+			factory: func(_ *config.Config) (income.AbstractManager, outcome.AbstractManager, error) {
+				return nil, nil, errors.New("mocked")
+			},
+		}
+		in, out := r.Reload(prev)
+		assert.Nil(in)
+		assert.Nil(out)
+		assert.Equal(r.cfg, current)
+		prev.AssertCalled(t, "SendTo", "admin", mock.Anything)
+		// 反映していないため、次の確認の際に読み込み直す
+		w.AssertNotCalled(t, "Accept")
+	})
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v38/github"
+)
+
+const (
+	Timeout = 5 * time.Second
+)
+
+// token が空の場合は nil を返す
+func NewClient(token, baseUrl string) (AbstractClient, error) {
+	if token == "" {
+		return nil, nil
+	}
+	c := &GitHubClient{}
+	if err := c.Init(token, baseUrl); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type AbstractClient interface {
+	Init(token, baseUrl string) error
+	CompareCommits(owner, repo, base, head string) ([]*github.RepositoryCommit, error)
+	CountReviewComments(owner, repo string, number int, reviewID int64) (int, error)
+	GetDismissalMessage(owner, repo string, number int, reviewID int64) (string, error)
+	GetIssue(owner, repo string, number int) (*github.Issue, error)
+	GetBranchSHA(owner, repo, branch string) (string, error)
+	GetFile(owner, repo, path, ref string) ([]byte, error)
+}
+
+type GitHubClient struct {
+	client *github.Client
+}
+
+func (gc *GitHubClient) Init(token, baseUrl string) error {
+	gc.client = github.NewClient(
+		&http.Client{Transport: &tokenTransport{token: token}, Timeout: Timeout},
+	)
+	if baseUrl != "" {
+		if !strings.HasSuffix(baseUrl, "/") {
+			baseUrl += "/"
+		}
+		u, err := url.Parse(baseUrl)
+		if err != nil {
+			return err
+		}
+		gc.client.BaseURL = u
+	}
+	return nil
+}
+
+func (gc *GitHubClient) CompareCommits(owner, repo, base, head string) ([]*github.RepositoryCommit, error) {
+	comp, _, err := gc.client.Repositories.CompareCommits(context.Background(), owner, repo, base, head, nil)
+	if err != nil {
+		return nil, err
+	}
+	return comp.Commits, nil
+}
+
+func (gc *GitHubClient) CountReviewComments(owner, repo string, number int, reviewID int64) (int, error) {
+	count := 0
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		comments, resp, err := gc.client.PullRequests.ListReviewComments(context.Background(), owner, repo, number, reviewID, opts)
+		if err != nil {
+			return 0, err
+		}
+		count += len(comments)
+		if resp.NextPage == 0 {
+			return count, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// Webhook には却下理由が含まれないため、Issue のイベントから取得する
+func (gc *GitHubClient) GetDismissalMessage(owner, repo string, number int, reviewID int64) (string, error) {
+	message := ""
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		events, resp, err := gc.client.Issues.ListIssueEvents(context.Background(), owner, repo, number, opts)
+		if err != nil {
+			return "", err
+		}
+		for _, e := range events {
+			if e.GetEvent() == "review_dismissed" && e.GetDismissedReview().GetReviewID() == reviewID {
+				message = e.GetDismissedReview().GetDismissalMessage()
+			}
+		}
+		if resp.NextPage == 0 {
+			return message, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// 譲渡された Issue はリダイレクトされるため、譲渡後の Issue が返る
+func (gc *GitHubClient) GetIssue(owner, repo string, number int) (*github.Issue, error) {
+	issue, _, err := gc.client.Issues.Get(context.Background(), owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+func (gc *GitHubClient) GetBranchSHA(owner, repo, branch string) (string, error) {
+	ref, _, err := gc.client.Git.GetRef(context.Background(), owner, repo, "heads/"+branch)
+	if err != nil {
+		return "", err
+	}
+	return ref.GetObject().GetSHA(), nil
+}
+
+// ファイルが存在しない場合は nil を返す
+func (gc *GitHubClient) GetFile(owner, repo, path, ref string) ([]byte, error) {
+	file, _, resp, err := gc.client.Repositories.GetContents(
+		context.Background(), owner, repo, path, &github.RepositoryContentGetOptions{Ref: ref},
+	)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// ディレクトリの場合
+	if file == nil {
+		return nil, nil
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
+
+type tokenTransport struct {
+	token string
+}
+
+func (tt *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "token "+tt.token)
+	return http.DefaultTransport.RoundTrip(r)
+}
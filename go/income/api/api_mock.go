@@ -0,0 +1,48 @@
+package api
+
+import (
+	"github.com/google/go-github/v38/github"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockedClient struct {
+	mock.Mock
+}
+
+func (m *MockedClient) Init(token, baseUrl string) error {
+	args := m.Called(token, baseUrl)
+	return args.Error(0)
+}
+
+func (m *MockedClient) CompareCommits(owner, repo, base, head string) ([]*github.RepositoryCommit, error) {
+	args := m.Called(owner, repo, base, head)
+	commits, _ := args[0].([]*github.RepositoryCommit)
+	return commits, args.Error(1)
+}
+
+func (m *MockedClient) CountReviewComments(owner, repo string, number int, reviewID int64) (int, error) {
+	args := m.Called(owner, repo, number, reviewID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockedClient) GetDismissalMessage(owner, repo string, number int, reviewID int64) (string, error) {
+	args := m.Called(owner, repo, number, reviewID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockedClient) GetIssue(owner, repo string, number int) (*github.Issue, error) {
+	args := m.Called(owner, repo, number)
+	issue, _ := args[0].(*github.Issue)
+	return issue, args.Error(1)
+}
+
+func (m *MockedClient) GetBranchSHA(owner, repo, branch string) (string, error) {
+	args := m.Called(owner, repo, branch)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockedClient) GetFile(owner, repo, path, ref string) ([]byte, error) {
+	args := m.Called(owner, repo, path, ref)
+	content, _ := args[0].([]byte)
+	return content, args.Error(1)
+}
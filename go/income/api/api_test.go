@@ -0,0 +1,182 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClient(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("without token", func(t *testing.T) {
+		c, err := NewClient("", "")
+		assert.Nil(err)
+		assert.Nil(c)
+	})
+
+	t.Run("with token", func(t *testing.T) {
+		c, err := NewClient("xxx", "")
+		assert.Nil(err)
+		assert.IsType(c, &GitHubClient{})
+	})
+}
+
+// GitHub API のスタブ
+func newFakeGitHub(t *testing.T, handler http.HandlerFunc) *GitHubClient {
+	s := httptest.NewServer(handler)
+	t.Cleanup(s.Close)
+
+	c := &GitHubClient{}
+	if err := c.Init("xxx", s.URL); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestGitHubClientCompareCommits(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	t.Run("ok", func(t *testing.T) {
+		c := newFakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(r.URL.Path, "/repos/Codertocat/Hello-World/compare/aaa...bbb")
+			assert.Equal(r.Header.Get("Authorization"), "token xxx")
+			fmt.Fprint(w, `{"commits":[{"sha":"bbb","html_url":"https://github.com/Codertocat/Hello-World/commit/bbb","commit":{"message":"Fix typo"}}]}`)
+		})
+
+		commits, err := c.CompareCommits("Codertocat", "Hello-World", "aaa", "bbb")
+		assert.Nil(err)
+		assert.Equal(len(commits), 1)
+		assert.Equal(commits[0].GetSHA(), "bbb")
+		assert.Equal(commits[0].GetCommit().GetMessage(), "Fix typo")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c := newFakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		})
+
+		_, err := c.CompareCommits("Codertocat", "Hello-World", "aaa", "bbb")
+		assert.NotNil(err)
+	})
+}
+
+func TestGitHubClientCountReviewComments(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	var base string
+	c := newFakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(r.URL.Path, "/repos/Codertocat/Hello-World/pulls/2/reviews/80/comments")
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=2>; rel="next"`, base, r.URL.Path))
+			fmt.Fprint(w, `[{"id":1},{"id":2}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"id":3}]`)
+	})
+	base = strings.TrimSuffix(c.client.BaseURL.String(), "/")
+
+	count, err := c.CountReviewComments("Codertocat", "Hello-World", 2, 80)
+	assert.Nil(err)
+	assert.Equal(count, 3)
+}
+
+func TestGitHubClientGetDismissalMessage(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	c := newFakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(r.URL.Path, "/repos/Codertocat/Hello-World/issues/2/events")
+		fmt.Fprint(w, `[
+			{"event":"labeled"},
+			{"event":"review_dismissed","dismissed_review":{"state":"approved","review_id":79,"dismissal_message":"Other"}},
+			{"event":"review_dismissed","dismissed_review":{"state":"approved","review_id":80,"dismissal_message":"Outdated"}}
+		]`)
+	})
+
+	message, err := c.GetDismissalMessage("Codertocat", "Hello-World", 2, 80)
+	assert.Nil(err)
+	assert.Equal(message, "Outdated")
+}
+
+func TestGitHubClientGetIssue(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	// 譲渡された Issue は譲渡先へリダイレクトされる
+	c := newFakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/Codertocat/Hello-World/issues/1":
+			http.Redirect(w, r, "/repositories/186853002/issues/5", http.StatusMovedPermanently)
+		case "/repositories/186853002/issues/5":
+			fmt.Fprint(w, `{"number":5,"html_url":"https://github.com/Octocoders/Hello-World/issues/5"}`)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	})
+
+	issue, err := c.GetIssue("Codertocat", "Hello-World", 1)
+	assert.Nil(err)
+	assert.Equal(issue.GetNumber(), 5)
+	assert.Equal(issue.GetHTMLURL(), "https://github.com/Octocoders/Hello-World/issues/5")
+}
+
+func TestGitHubClientGetBranchSHA(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	c := newFakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(r.URL.Path, "/repos/Codertocat/Hello-World/git/ref/heads/main")
+		fmt.Fprint(w, `{"ref":"refs/heads/main","object":{"type":"commit","sha":"aaa"}}`)
+	})
+
+	sha, err := c.GetBranchSHA("Codertocat", "Hello-World", "main")
+	assert.Nil(err)
+	assert.Equal(sha, "aaa")
+}
+
+func TestGitHubClientGetFile(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	t.Run("ok", func(t *testing.T) {
+		c := newFakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(r.URL.Path, "/repos/Codertocat/Hello-World/contents/.github/ggnb.yml")
+			assert.Equal(r.URL.Query().Get("ref"), "aaa")
+			// "route: frontend\n"
+			fmt.Fprint(w, `{"type":"file","encoding":"base64","content":"cm91dGU6IGZyb250ZW5kCg=="}`)
+		})
+
+		content, err := c.GetFile("Codertocat", "Hello-World", ".github/ggnb.yml", "aaa")
+		assert.Nil(err)
+		assert.Equal(string(content), "route: frontend\n")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		c := newFakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		})
+
+		content, err := c.GetFile("Codertocat", "Hello-World", ".github/ggnb.yml", "aaa")
+		assert.Nil(err)
+		assert.Nil(content)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c := newFakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message":"Server Error"}`)
+		})
+
+		_, err := c.GetFile("Codertocat", "Hello-World", ".github/ggnb.yml", "aaa")
+		assert.NotNil(err)
+	})
+}
@@ -4,16 +4,53 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SongCastle/ggnb/income/i18n"
 )
 
 const (
 	Color = "#2eb67d"
+	// 警告 (WarningColor) と区別する
+	CelebrationColor = "#9b59b6"
+	WarningColor = "#ecb22e"
+	ApprovedColor = "#2eb67d"
+	ChangesRequestedColor = "#e01e5a"
+	CommentedColor = "#36c5f0"
+	DismissedColor = "#868686"
+	DraftColor = "#868686"
+	ForcePushColor = "#e01e5a"
 	ErrorColor = "#e01e5a"
 	Fallback = "GitHub Notifitation"
 	TitileLink = "https://github.com/SongCastle/ggnb"
 	Title = "GitHub Notification"
+	// 切り詰めた値の末尾に付ける
+	Truncated = "…(truncated)"
 )
 
+// 通知先ごとの上限 (超えた場合は Build で切り詰める)
+type Limits struct {
+	// 項目の値の文字数
+	FieldValue int
+	// 項目数
+	Fields int
+	// payload (JSON) のバイト数
+	PayloadSize int
+}
+
+// Slack は上限を超える payload を invalid_payload として拒否する
+var SlackLimits = Limits{FieldValue: 2000, Fields: 20, PayloadSize: 30000}
+
+// まとめて通知する際の 1 つの payload の attachment 数
+const MaxAttachments = 20
+
+// 切り詰める際に残す最小の文字数
+const minFieldValue = 100
+
+var bareURL = regexp.MustCompile(`^https?://\S+$`)
+
 type field struct {
 	Title *string `json:"title"`
 	Value *string `json:"value"`
@@ -26,6 +63,9 @@ type attachment struct {
 	Fields []*field `json:"fields"`
 	TitileLink *string `json:"title_link"`
 	Title *string `json:"title"`
+	limits Limits
+	// 切り詰めた場合に案内する全文の URL
+	link string
 }
 
 func (a *attachment) InsertField(title, value string, short ...bool) {
@@ -37,9 +77,112 @@ func (a *attachment) InsertField(title, value string, short ...bool) {
 	}
 }
 
+func (a *attachment) SetColor(color string) {
+	a.Color = toP(color)
+}
+
+// 切り詰めた場合に案内する URL (未設定の場合は値が URL のみの項目のうち最後のもの)
+func (a *attachment) SetLink(url string) {
+	a.link = url
+}
+
 func (a *attachment) Build() (*bytes.Buffer, error) {
+	a.truncateFields()
 	p := payloadBuilder{Attachments: []*attachment{a}}
-	return p.Build()
+	buf, err := p.Build()
+	if err != nil {
+		return nil, err
+	}
+	// 上限に収まるまで最も長い項目を半分にする
+	for a.limits.PayloadSize > 0 && buf.Len() > a.limits.PayloadSize {
+		if !a.shrinkLongestField() {
+			break
+		}
+		if buf, err = p.Build(); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+func (a *attachment) fullContentLink() string {
+	if a.link != "" {
+		return a.link
+	}
+	for i := len(a.Fields) - 1; i >= 0; i-- {
+		if v := *a.Fields[i].Value; bareURL.MatchString(v) {
+			return v
+		}
+	}
+	return ""
+}
+
+func (a *attachment) truncateFields() {
+	link := a.fullContentLink()
+	if max := a.limits.FieldValue; max > 0 {
+		for _, f := range a.Fields {
+			if utf8.RuneCountInString(*f.Value) > max {
+				f.Value = toP(truncate(*f.Value, max, link))
+			}
+		}
+	}
+	if max := a.limits.Fields; max > 0 && len(a.Fields) > max {
+		rest := len(a.Fields) - max + 1
+		a.Fields = append(
+			a.Fields[:max-1],
+			&field{Title: toP(Truncated), Value: toP(withLink(fmt.Sprintf("+%d", rest), link))},
+		)
+	}
+}
+
+// 切り詰められる項目がない場合は false を返す
+func (a *attachment) shrinkLongestField() bool {
+	var longest *field
+	length := 0
+	for _, f := range a.Fields {
+		if n := utf8.RuneCountInString(*f.Value); n > length {
+			longest, length = f, n
+		}
+	}
+	if longest == nil || length/2 < minFieldValue {
+		return false
+	}
+	longest.Value = toP(truncate(*longest.Value, length/2, a.fullContentLink()))
+	return true
+}
+
+// s を (末尾の案内を含めて) max 文字以内に切り詰める
+// リンク (<URL|テキスト>)・文字参照・コードブロックが途中で途切れないようにする
+func truncate(s string, max int, link string) string {
+	suffix := withLink(Truncated, link)
+	// 改行と閉じられていないコードブロックの "```" の分も空けておく
+	n := max - utf8.RuneCountInString(suffix) - len("\n```\n")
+	if n < 0 {
+		n = 0
+	}
+	runes := []rune(s)
+	if n >= len(runes) {
+		return s
+	}
+	head := string(runes[:n])
+	if i := strings.LastIndex(head, "<"); i > strings.LastIndex(head, ">") {
+		head = head[:i]
+	}
+	if i := strings.LastIndex(head, "&"); i > strings.LastIndex(head, ";") {
+		head = head[:i]
+	}
+	head = strings.TrimRight(head, " \n")
+	if strings.Count(head, "```")%2 == 1 {
+		head += "\n```"
+	}
+	return head + "\n" + suffix
+}
+
+func withLink(s, link string) string {
+	if link == "" {
+		return s
+	}
+	return fmt.Sprintf("%s <%s>", s, link)
 }
 
 type payloadBuilder struct {
@@ -60,16 +203,78 @@ func NewAttachment() *attachment {
 		Fallback: toP(Fallback),
 		TitileLink: toP(TitileLink),
 		Title: toP(Title),
+		limits: SlackLimits,
 	}
 }
 
 func BuildError(err error) (*bytes.Buffer, error) {
 	a := NewAttachment()
 	a.Color = toP(ErrorColor)
-	a.InsertField("エラー", fmt.Sprintf("%v", err))
+	l := i18n.NewLocalizer(i18n.RouteLocale(""))
+	a.InsertField(l.T("field.error"), fmt.Sprintf("%v", err))
+	return a.Build()
+}
+
+// 設定ファイルを読み込み直したことを通知する (失敗した場合は err)
+func BuildConfigReload(route, file string, err error) (*bytes.Buffer, error) {
+	a := NewAttachment()
+	l := i18n.NewLocalizer(i18n.RouteLocale(route))
+	if err != nil {
+		a.Color = toP(ErrorColor)
+		a.InsertField(l.T("config.reload_failed"), fmt.Sprintf("%v", err))
+	} else {
+		a.InsertField(l.T("config.reloaded"), file)
+	}
 	return a.Build()
 }
 
+// 通知を控えていた間の通知を、件数の見出しを付けてまとめる (上限を超える場合は複数の payload に分ける)
+// attachments を持たない payload (テンプレート等) はそのまま返す
+func BuildQueued(route string, payloads [][]byte) ([]*bytes.Buffer, error) {
+	a := NewAttachment()
+	l := i18n.NewLocalizer(i18n.RouteLocale(route))
+	a.InsertField(l.T("field.quiet_hours"), l.T("quiet_hours.queued", len(payloads)))
+	header, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	var bufs, others []*bytes.Buffer
+	group, size := []json.RawMessage{header}, len(header)
+	flush := func() error {
+		j, err := json.Marshal(map[string][]json.RawMessage{"attachments": group})
+		if err != nil {
+			return err
+		}
+		bufs = append(bufs, bytes.NewBuffer(j))
+		group, size = nil, 0
+		return nil
+	}
+	for _, p := range payloads {
+		var parsed struct {
+			Attachments []json.RawMessage `json:"attachments"`
+		}
+		if json.Unmarshal(p, &parsed) != nil || len(parsed.Attachments) == 0 {
+			others = append(others, bytes.NewBuffer(p))
+			continue
+		}
+		for _, at := range parsed.Attachments {
+			if len(group) >= MaxAttachments || (len(group) > 0 && size+len(at) > SlackLimits.PayloadSize) {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+			group, size = append(group, at), size+len(at)
+		}
+	}
+	if len(group) > 0 {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	return append(bufs, others...), nil
+}
+
 func getShort(short ...bool) bool {
 	if len(short) == 0 {
 		return false
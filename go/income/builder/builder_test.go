@@ -32,6 +32,14 @@ func TestAttachmentInsertField(t *testing.T) {
 	assert.Equal(a.Fields[2].Short, false)
 }
 
+func TestAttachmentSetColor(t *testing.T) {
+	t.Parallel()
+
+	a := NewAttachment()
+	a.SetColor(ErrorColor)
+	assert.Equal(t, a.Color, toP(ErrorColor))
+}
+
 func TestAttachmentBuild(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
@@ -162,6 +170,48 @@ func TestBuildError(t *testing.T) {
 	assert.Equal(buf, bytes.NewBufferString (msg))
 }
 
+func TestBuildConfigReload(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	build := func(color, title, value string) *bytes.Buffer {
+		msg :=
+			fmt.Sprintf(
+				`
+					{
+						"attachments":
+							[
+								{
+									"color":"%s",
+									"fallback":"%s",
+									"fields":[
+										{"title":"%s","value":"%s","short":false}
+									],
+									"title_link":"%s",
+									"title":"%s"
+								}
+							]
+					}
+				`, color, Fallback, title, value, TitileLink, Title,
+			)
+		msg = strings.ReplaceAll(msg, "\t", "")
+		msg = strings.ReplaceAll(msg, "\n", "")
+		return bytes.NewBufferString(msg)
+	}
+
+	t.Run("reloaded", func(t *testing.T) {
+		buf, err := BuildConfigReload("", "config.yml", nil)
+		assert.Nil(err)
+		assert.Equal(buf, build(Color, "設定を再読み込みしました", "config.yml"))
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		buf, err := BuildConfigReload("", "config.yml", errors.New("test"))
+		assert.Nil(err)
+		assert.Equal(buf, build(ErrorColor, "設定の再読み込みに失敗しました (以前の設定で通知します)", "test"))
+	})
+}
+
 func TestGetShort(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
@@ -189,3 +239,107 @@ func TestToP(t *testing.T) {
 	s := "test"
 	assert.Equal(t, toP(s), &s)
 }
+
+func TestAttachmentBuildLimits(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	link := "https://github.com/Codertocat/Hello-World/pull/2"
+
+	t.Run("field value", func(t *testing.T) {
+		a := NewAttachment()
+		a.limits = Limits{FieldValue: 100}
+		a.InsertField("Body", strings.Repeat("a", 10) + " <https://example.com|example> " + strings.Repeat("b", 100))
+		a.InsertField("Link", link)
+		_, err := a.Build()
+		assert.Nil(err)
+
+		// リンクの途中では切らない
+		assert.Equal(*a.Fields[0].Value, strings.Repeat("a", 10) + "\n" + Truncated + " <" + link + ">")
+		assert.Equal(*a.Fields[1].Value, link)
+	})
+
+	t.Run("code block", func(t *testing.T) {
+		a := NewAttachment()
+		a.limits = Limits{FieldValue: 30}
+		a.InsertField("Body", "```\n" + strings.Repeat("c", 50) + "\n```")
+		_, err := a.Build()
+		assert.Nil(err)
+
+		value := *a.Fields[0].Value
+		assert.Equal(value, "```\n" + strings.Repeat("c", 9) + "\n```\n" + Truncated)
+		assert.LessOrEqual(len([]rune(value)), 30)
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		a := NewAttachment()
+		a.limits = Limits{Fields: 3}
+		for i := 0; i < 5; i++ {
+			a.InsertField(fmt.Sprintf("Field %d", i), "value")
+		}
+		a.SetLink(link)
+		_, err := a.Build()
+		assert.Nil(err)
+
+		assert.Equal(len(a.Fields), 3)
+		assert.Equal(*a.Fields[1].Title, "Field 1")
+		assert.Equal(*a.Fields[2].Title, Truncated)
+		assert.Equal(*a.Fields[2].Value, "+3 <" + link + ">")
+	})
+
+	t.Run("payload size", func(t *testing.T) {
+		a := NewAttachment()
+		a.limits = Limits{PayloadSize: 1000}
+		a.InsertField("Before", strings.Repeat("x", 600))
+		a.InsertField("After", strings.Repeat("y", 600))
+		buf, err := a.Build()
+		assert.Nil(err)
+
+		assert.LessOrEqual(buf.Len(), 1000)
+		assert.Contains(*a.Fields[0].Value, Truncated)
+	})
+
+	t.Run("within limits", func(t *testing.T) {
+		a := NewAttachment()
+		a.InsertField("Body", strings.Repeat("z", SlackLimits.FieldValue))
+		_, err := a.Build()
+		assert.Nil(err)
+		assert.Equal(*a.Fields[0].Value, strings.Repeat("z", SlackLimits.FieldValue))
+	})
+}
+
+func TestBuildQueued(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	header := fmt.Sprintf(
+		`{"color":"%s","fallback":"%s","fields":[{"title":"通知を控える時間帯","value":"%s","short":false}],"title_link":"%s","title":"%s"}`,
+		Color, Fallback, "%s", TitileLink, Title,
+	)
+
+	t.Run("merge", func(t *testing.T) {
+		bufs, err := BuildQueued("", [][]byte{
+			[]byte(`{"attachments":[{"title":"1"}]}`),
+			[]byte(`{"attachments":[{"title":"2"},{"title":"3"}]}`),
+			// attachments を持たない payload はそのまま
+			[]byte(`{"text":"4"}`),
+		})
+		assert.Nil(err)
+		assert.Equal(len(bufs), 2)
+		assert.Equal(
+			bufs[0].String(),
+			fmt.Sprintf(`{"attachments":[`+header+`,{"title":"1"},{"title":"2"},{"title":"3"}]}`, "通知を控えていた間に 3 件の通知がありました"),
+		)
+		assert.Equal(bufs[1].String(), `{"text":"4"}`)
+	})
+
+	t.Run("split", func(t *testing.T) {
+		var payloads [][]byte
+		for i := 0; i < MaxAttachments*2; i++ {
+			payloads = append(payloads, []byte(fmt.Sprintf(`{"attachments":[{"title":"%d"}]}`, i)))
+		}
+		bufs, err := BuildQueued("", payloads)
+		assert.Nil(err)
+		assert.Equal(len(bufs), 3)
+		assert.Contains(bufs[2].String(), fmt.Sprintf(`{"title":"%d"}`, MaxAttachments*2-1))
+	})
+}
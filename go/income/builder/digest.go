@@ -0,0 +1,114 @@
+package builder
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SongCastle/ggnb/income/i18n"
+)
+
+const (
+	// 項目ごとに一覧にする件数
+	DigestListLimit = 10
+	DigestContributors = 5
+	DigestTimeLayout = "2006-01-02 15:04"
+)
+
+// ダイジェストに記録する Event の概要
+type DigestEntry struct {
+	Event string `json:"event"`
+	// マージされた PR は merged
+	Action string `json:"action"`
+	Title string `json:"title,omitempty"`
+	URL string `json:"url,omitempty"`
+	Sender string `json:"sender,omitempty"`
+	SenderType string `json:"sender_type,omitempty"`
+}
+
+func (e *DigestEntry) line() string {
+	line := e.Title
+	if e.URL != "" {
+		line = fmt.Sprintf("<%s|%s>", e.URL, e.Title)
+	}
+	if e.Sender != "" {
+		line += fmt.Sprintf(" (%s)", e.Sender)
+	}
+	return line
+}
+
+// from から to までの Event を、PR・Issue・リリース・コントリビューターごとにまとめる
+func BuildDigest(route string, from, to time.Time, entries []*DigestEntry) (*bytes.Buffer, error) {
+	a := NewAttachment()
+	l := i18n.NewLocalizer(i18n.RouteLocale(route))
+	a.InsertField(
+		l.T("field.period"),
+		l.T("digest.period", from.Format(DigestTimeLayout), to.Format(DigestTimeLayout), len(entries)),
+	)
+
+	groups := map[string][]*DigestEntry{}
+	others := map[string]int{}
+	contributors := map[string]int{}
+	for _, e := range entries {
+		switch key := e.Event + "." + e.Action; key {
+		case "pull_request.opened", "pull_request.merged", "issues.opened", "issues.closed", "release.published":
+			groups[key] = append(groups[key], e)
+		default:
+			others[e.Event]++
+		}
+		if e.Sender != "" && e.SenderType != "Bot" {
+			contributors[e.Sender]++
+		}
+	}
+	for _, g := range []struct {
+		key string
+		title string
+	}{
+		{"pull_request.opened", "field.pull_requests_opened"},
+		{"pull_request.merged", "field.pull_requests_merged"},
+		{"issues.opened", "field.issues_opened"},
+		{"issues.closed", "field.issues_closed"},
+		{"release.published", "field.releases"},
+	} {
+		a.InsertField(l.T(g.title), digestList(l, groups[g.key]))
+	}
+	a.InsertField(l.T("field.top_contributors"), rankCounts(contributors, DigestContributors))
+	a.InsertField(l.T("field.other_events"), rankCounts(others, 0))
+	return a.Build()
+}
+
+func digestList(l *i18n.Localizer, entries []*DigestEntry) string {
+	var lines []string
+	for i, e := range entries {
+		if i == DigestListLimit {
+			lines = append(lines, l.T("digest.more", len(entries)-DigestListLimit))
+			break
+		}
+		lines = append(lines, e.line())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// 多い順に <名前>: <件数> をカンマで区切る (limit が 0 の場合はすべて)
+func rankCounts(counts map[string]int, limit int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if limit > 0 && len(names) > limit {
+		names = names[:limit]
+	}
+	ranks := make([]string, len(names))
+	for i, name := range names {
+		ranks[i] = fmt.Sprintf("%s: %d", name, counts[name])
+	}
+	return strings.Join(ranks, ", ")
+}
@@ -0,0 +1,57 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDigest(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	from := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	entries := []*DigestEntry{
+		{Event: "pull_request", Action: "opened", Title: "Add digest", URL: "https://github.com/Codertocat/Hello-World/pull/2", Sender: "Codertocat"},
+		{Event: "pull_request", Action: "merged", Title: "Fix typo", URL: "https://github.com/Codertocat/Hello-World/pull/1", Sender: "octocat"},
+		{Event: "issues", Action: "opened", Title: "Spelling error", Sender: "Codertocat"},
+		{Event: "release", Action: "published", Title: "v1.0.0", Sender: "Codertocat"},
+		{Event: "push", Action: "", Sender: "dependabot[bot]", SenderType: "Bot"},
+	}
+	for i := 0; i < DigestListLimit+2; i++ {
+		entries = append(entries, &DigestEntry{Event: "issues", Action: "closed", Title: fmt.Sprintf("Issue %d", i), Sender: "octocat"})
+	}
+
+	buf, err := BuildDigest("", from, to, entries)
+	assert.Nil(err)
+
+	var payload struct {
+		Attachments []struct {
+			Fields []struct {
+				Title string `json:"title"`
+				Value string `json:"value"`
+			} `json:"fields"`
+		} `json:"attachments"`
+	}
+	assert.Nil(json.Unmarshal(buf.Bytes(), &payload))
+	fields := map[string]string{}
+	var titles []string
+	for _, f := range payload.Attachments[0].Fields {
+		fields[f.Title] = f.Value
+		titles = append(titles, f.Title)
+	}
+	// 該当する Event がない項目は含めない
+	assert.Equal(titles, []string{"期間", "PR (オープン)", "PR (マージ)", "Issue (オープン)", "Issue (クローズ)", "リリース", "コントリビューター", "その他の Event"})
+	assert.Equal(fields["期間"], "2026-10-19 09:00 〜 2026-10-20 09:00 (17 件)")
+	assert.Equal(fields["PR (オープン)"], "<https://github.com/Codertocat/Hello-World/pull/2|Add digest> (Codertocat)")
+	assert.Equal(fields["Issue (オープン)"], "Spelling error (Codertocat)")
+	assert.Contains(fields["Issue (クローズ)"], "Issue 9 (octocat)\n…他 2 件")
+	assert.NotContains(fields["Issue (クローズ)"], "Issue 10")
+	// Bot は除く
+	assert.Equal(fields["コントリビューター"], "octocat: 13, Codertocat: 3")
+	assert.Equal(fields["その他の Event"], "push: 1")
+}
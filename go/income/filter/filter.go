@@ -0,0 +1,190 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	Include = "include"
+	Exclude = "exclude"
+)
+
+// ルールの条件に指定できる項目
+var keys = map[string]bool{
+	"sender": true,
+	"sender.type": true,
+	"event": true,
+	"action": true,
+	"ref": true,
+	"label": true,
+	"path": true,
+	"repository": true,
+}
+
+// ルールが設定されていない場合は nil を返す
+// dryRun の場合、除外する Event もログに出力するのみで通知する
+func NewFilter(rules []string, dryRun bool) (AbstractFilter, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	f := &RuleFilter{}
+	if err := f.Init(rules, dryRun); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+type AbstractFilter interface {
+	Init(rules []string, dryRun bool) error
+	// 通知しない場合は false を返す
+	Allow(e *Event) bool
+}
+
+// ルールの判定に用いる Event の情報
+type Event struct {
+	Sender string
+	SenderType string
+	Event string
+	Action string
+	Ref string
+	Labels []string
+	Paths []string
+	Repository string
+}
+
+func (e *Event) values(key string) []string {
+	switch key {
+	case "sender":
+		return []string{e.Sender}
+	case "sender.type":
+		return []string{e.SenderType}
+	case "event":
+		return []string{e.Event}
+	case "action":
+		return []string{e.Action}
+	case "ref":
+		return []string{e.Ref}
+	case "label":
+		return e.Labels
+	case "path":
+		return e.Paths
+	case "repository":
+		return []string{e.Repository}
+	}
+	return nil
+}
+
+// include/exclude <項目>=<パターン>[,<パターン>...] ...
+// 条件はすべて満たす場合に一致し、パターンはいずれかに一致する場合に一致する
+type Rule struct {
+	Text string
+	Exclude bool
+	conditions []condition
+}
+
+type condition struct {
+	key string
+	patterns []string
+}
+
+func (r *Rule) Match(e *Event) bool {
+	for _, c := range r.conditions {
+		if !c.match(e.values(c.key)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *condition) match(values []string) bool {
+	for _, v := range values {
+		for _, p := range c.patterns {
+			if matchPattern(p, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dependabot[bot] 等を指定できるよう、完全に一致する場合はパターンとして扱わない
+// <ディレクトリ>/** はディレクトリ以下のすべてのパスに一致する
+func matchPattern(p, v string) bool {
+	if p == v {
+		return true
+	}
+	if dir := strings.TrimSuffix(p, "**"); dir != p && strings.HasSuffix(dir, "/") {
+		return strings.HasPrefix(v, dir)
+	}
+	ok, _ := path.Match(p, v)
+	return ok
+}
+
+func ParseRule(text string) (*Rule, error) {
+	words := strings.Fields(text)
+	if len(words) < 2 {
+		return nil, errors.New(fmt.Sprintf("Invalid rule: %s", text))
+	}
+	r := &Rule{Text: strings.Join(words, " ")}
+	switch words[0] {
+	case Include:
+	case Exclude:
+		r.Exclude = true
+	default:
+		return nil, errors.New(fmt.Sprintf("Invalid rule: %s", text))
+	}
+	for _, w := range words[1:] {
+		kv := strings.SplitN(w, "=", 2)
+		if len(kv) != 2 || !keys[kv[0]] || kv[1] == "" {
+			return nil, errors.New(fmt.Sprintf("Invalid rule: %s", text))
+		}
+		c := condition{key: kv[0], patterns: strings.Split(kv[1], ",")}
+		for _, p := range c.patterns {
+			if _, err := path.Match(p, ""); err != nil || p == "" {
+				return nil, errors.New(fmt.Sprintf("Invalid rule: %s", text))
+			}
+		}
+		r.conditions = append(r.conditions, c)
+	}
+	return r, nil
+}
+
+type RuleFilter struct {
+	rules []*Rule
+	dryRun bool
+}
+
+func (rf *RuleFilter) Init(rules []string, dryRun bool) error {
+	rf.rules = nil
+	rf.dryRun = dryRun
+	for _, text := range rules {
+		r, err := ParseRule(text)
+		if err != nil {
+			return err
+		}
+		rf.rules = append(rf.rules, r)
+	}
+	return nil
+}
+
+// 最初に一致したルールに従う (一致するルールがない場合は通知する)
+func (rf *RuleFilter) Allow(e *Event) bool {
+	for i, r := range rf.rules {
+		if !r.Match(e) {
+			continue
+		}
+		if !r.Exclude {
+			return true
+		}
+		if rf.dryRun {
+			fmt.Printf("Filter (dry-run): %s (%s) would be dropped by rule #%d: %s\n", e.Event, e.Action, i+1, r.Text)
+			return true
+		}
+		fmt.Printf("Filter: %s (%s) dropped by rule #%d: %s\n", e.Event, e.Action, i+1, r.Text)
+		return false
+	}
+	return true
+}
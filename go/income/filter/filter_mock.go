@@ -0,0 +1,19 @@
+package filter
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type MockedFilter struct {
+	mock.Mock
+}
+
+func (m *MockedFilter) Init(rules []string, dryRun bool) error {
+	args := m.Called(rules, dryRun)
+	return args.Error(0)
+}
+
+func (m *MockedFilter) Allow(e *Event) bool {
+	args := m.Called(e)
+	return args.Bool(0)
+}
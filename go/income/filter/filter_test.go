@@ -0,0 +1,122 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("without rules", func(t *testing.T) {
+		f, err := NewFilter(nil, false)
+		assert.Nil(err)
+		assert.Nil(f)
+	})
+
+	t.Run("with rules", func(t *testing.T) {
+		f, err := NewFilter([]string{"exclude sender.type=Bot"}, false)
+		assert.Nil(err)
+		assert.IsType(f, &RuleFilter{})
+	})
+
+	t.Run("with invalid rules", func(t *testing.T) {
+		f, err := NewFilter([]string{"drop sender.type=Bot"}, false)
+		assert.EqualError(err, "Invalid rule: drop sender.type=Bot")
+		assert.Nil(f)
+	})
+}
+
+func TestParseRule(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	r, err := ParseRule("  exclude  event=issues   action=labeled,unlabeled ")
+	assert.Nil(err)
+	assert.Equal(r.Text, "exclude event=issues action=labeled,unlabeled")
+	assert.True(r.Exclude)
+	assert.Equal(r.conditions, []condition{
+		{key: "event", patterns: []string{"issues"}},
+		{key: "action", patterns: []string{"labeled", "unlabeled"}},
+	})
+
+	for _, text := range []string{
+		"exclude",
+		"ignore event=issues",
+		"exclude branch=main",
+		"exclude event=",
+		"exclude event=issues,",
+		"exclude ref=[",
+	} {
+		_, err := ParseRule(text)
+		assert.NotNil(err, text)
+	}
+}
+
+func TestRuleMatch(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	e := &Event{
+		Sender: "dependabot[bot]",
+		SenderType: "Bot",
+		Event: "push",
+		Ref: "refs/heads/dependabot/npm/lodash",
+		Labels: []string{"dependencies", "javascript"},
+		Paths: []string{"docs/guide/setup.md", "package.json"},
+		Repository: "Codertocat/Hello-World",
+	}
+	for text, expected := range map[string]bool{
+		"exclude sender=dependabot[bot]": true,
+		"exclude sender=renovate[bot]": false,
+		"exclude sender.type=Bot event=push": true,
+		"exclude sender.type=Bot event=issues": false,
+		"exclude ref=refs/heads/dependabot/*": false,
+		"exclude ref=refs/heads/dependabot/**": true,
+		"exclude label=wip,dependencies": true,
+		"exclude path=docs/**": true,
+		"exclude path=*.json": true,
+		"exclude path=src/**": false,
+		"exclude repository=Codertocat/*": true,
+		"exclude action=opened": false,
+	} {
+		r, err := ParseRule(text)
+		assert.Nil(err)
+		assert.Equal(r.Match(e), expected, text)
+	}
+}
+
+func TestRuleFilterAllow(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	rules := []string{
+		"include sender=dependabot[bot] event=pull_request action=opened",
+		"exclude sender.type=Bot",
+		"exclude event=issues action=labeled,unlabeled",
+	}
+	bot := &Event{Sender: "dependabot[bot]", SenderType: "Bot", Event: "pull_request", Action: "opened"}
+	botSync := &Event{Sender: "dependabot[bot]", SenderType: "Bot", Event: "pull_request", Action: "synchronize"}
+	labeled := &Event{Sender: "Codertocat", SenderType: "User", Event: "issues", Action: "labeled"}
+	opened := &Event{Sender: "Codertocat", SenderType: "User", Event: "issues", Action: "opened"}
+
+	t.Run("filter", func(t *testing.T) {
+		f := &RuleFilter{}
+		assert.Nil(f.Init(rules, false))
+		assert.Equal(len(f.rules), 3)
+
+		assert.True(f.Allow(bot))
+		assert.False(f.Allow(botSync))
+		assert.False(f.Allow(labeled))
+		assert.True(f.Allow(opened))
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		f := &RuleFilter{}
+		assert.Nil(f.Init(rules, true))
+
+		assert.True(f.Allow(botSync))
+		assert.True(f.Allow(labeled))
+	})
+}
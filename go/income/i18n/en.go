@@ -0,0 +1,262 @@
+package i18n
+
+// 英語
+var en = Catalog{
+	"branch_protection_rule.allow_deletions":        "Branch deletion: %s\n",
+	"branch_protection_rule.allow_force_pushes":     "Force push: %s\n",
+	"branch_protection_rule.code_owner_review":      "Code owner review: %t\n",
+	"branch_protection_rule.created":                "Branch protection rule created",
+	"branch_protection_rule.deleted":                "Branch protection rule deleted",
+	"branch_protection_rule.edited":                 "Branch protection rule edited",
+	"branch_protection_rule.enforce_admins":         "Enforce for admins: %t\n",
+	"branch_protection_rule.required_reviews":       "Required reviews: %d\n",
+	"branch_protection_rule.required_status_checks": "Required status checks: %s\n",
+	"commit_comment.created":                        "Commented",
+	"config.reload_failed":                          "Failed to reload configuration (the previous one is kept)",
+	"config.reloaded":                               "Configuration reloaded",
+	"create.branch":                                 "Branch created",
+	"create.tag":                                    "Tag created",
+	"delete.branch":                                 "Branch deleted",
+	"delete.tag":                                    "Tag deleted",
+	"deployment_status.failure":                     "Deployment failed",
+	"deployment_status.success":                     "Deployment succeeded",
+	"digest.more":                                   "…and %d more",
+	"digest.period":                                 "%s – %s (%d events)",
+	"field.account":                                 "Account",
+	"field.action":                                  "Action",
+	"field.alert":                                   "Alert",
+	"field.author":                                  "Author",
+	"field.before":                                  "Before",
+	"field.before_deletion":                         "Before deletion",
+	"field.body":                                    "Body",
+	"field.body_from":                               "Body (before)",
+	"field.body_to":                                 "Body (after)",
+	"field.branch":                                  "Branch",
+	"field.branch_name":                             "Branch",
+	"field.changes":                                 "Changes",
+	"field.closed_issues":                           "Issues (closed)",
+	"field.color":                                   "Color",
+	"field.color_from":                              "Color (before)",
+	"field.color_to":                                "Color (after)",
+	"field.comment":                                 "Comment",
+	"field.comment_from":                            "Comment (before)",
+	"field.comment_to":                              "Comment (after)",
+	"field.commit":                                  "Commit",
+	"field.commit_id":                               "Commit ID",
+	"field.content_type":                            "Content type",
+	"field.description":                             "Description",
+	"field.description_from":                        "Description (before)",
+	"field.description_to":                          "Description (after)",
+	"field.digest":                                  "Digest",
+	"field.due_on":                                  "Due date",
+	"field.due_on_from":                             "Due date (before)",
+	"field.due_on_to":                               "Due date (after)",
+	"field.ecosystem":                               "Ecosystem",
+	"field.effective_date":                          "Effective date",
+	"field.enforcement":                             "Enforcement",
+	"field.environment":                             "Environment",
+	"field.error":                                   "Error",
+	"field.events":                                  "Events",
+	"field.field":                                   "Field",
+	"field.fork_to":                                 "Forked to",
+	"field.forks":                                   "Forks",
+	"field.free_trial":                              "Free trial",
+	"field.hook_id":                                 "Hook ID",
+	"field.inline_comments":                         "Inline comments",
+	"field.issues_closed":                           "Issues closed",
+	"field.issues_opened":                           "Issues opened",
+	"field.label":                                   "Label",
+	"field.label_from":                              "Label (before)",
+	"field.label_to":                                "Label (after)",
+	"field.link":                                    "Link",
+	"field.link_from":                               "Link (before)",
+	"field.link_to":                                 "Link (after)",
+	"field.milestone":                               "Milestone",
+	"field.milestone_from":                          "Milestone (before)",
+	"field.milestone_to":                            "Milestone (after)",
+	"field.open_issues":                             "Issues (open)",
+	"field.organization":                            "Organization",
+	"field.other_events":                            "Other events",
+	"field.owner_from":                              "Owner (before)",
+	"field.owner_to":                                "Owner (after)",
+	"field.package":                                 "Package",
+	"field.pages":                                   "Pages",
+	"field.period":                                  "Period",
+	"field.permission":                              "Permission",
+	"field.permission_from":                         "Permission (before)",
+	"field.permission_to":                           "Permission (after)",
+	"field.plan":                                    "Plan",
+	"field.privacy":                                 "Visibility",
+	"field.privacy_from":                            "Visibility (before)",
+	"field.privacy_to":                              "Visibility (after)",
+	"field.progress":                                "Progress",
+	"field.pull_request":                            "PR",
+	"field.pull_requests_merged":                    "Pull requests merged",
+	"field.pull_requests_opened":                    "Pull requests opened",
+	"field.quiet_hours":                             "Quiet hours",
+	"field.reason":                                  "Reason",
+	"field.ref":                                     "Ref",
+	"field.releases":                                "Releases",
+	"field.repository":                              "Repository",
+	"field.repository_name_from":                    "Repository (before)",
+	"field.repository_name_to":                      "Repository (after)",
+	"field.reviewers":                               "Reviewers",
+	"field.rules":                                   "Rules",
+	"field.ruleset":                                 "Ruleset",
+	"field.settings":                                "Settings",
+	"field.settings_from":                           "Settings (before)",
+	"field.settings_to":                             "Settings (after)",
+	"field.severity":                                "Severity",
+	"field.sponsor":                                 "Sponsor",
+	"field.stars":                                   "Stars",
+	"field.state":                                   "State",
+	"field.tag_name":                                "Tag",
+	"field.target":                                  "Target",
+	"field.target_branch":                           "Target branch",
+	"field.target_user":                             "User",
+	"field.team":                                    "Team",
+	"field.team_name_from":                          "Team (before)",
+	"field.team_name_to":                            "Team (after)",
+	"field.tier":                                    "Tier",
+	"field.title":                                   "Title",
+	"field.title_from":                              "Title (before)",
+	"field.title_to":                                "Title (after)",
+	"field.top_contributors":                        "Top contributors",
+	"field.transferred_to":                          "Transferred to",
+	"field.uploader":                                "Uploaded by",
+	"field.value_from":                              "Value (before)",
+	"field.value_to":                                "Value (after)",
+	"field.version":                                 "Version",
+	"field.warning":                                 "Warning",
+	"fork":                                          "Forked",
+	"format.change":                                 "%s → %s",
+	"format.count":                                  "%d",
+	"format.one_time":                               "%s / one-time",
+	"format.per_month":                              "%s / month",
+	"format.per_year":                               "%s / year",
+	"format.units":                                  " × %d %s",
+	"format.until":                                  "Until %s",
+	"gollum":                                        "Wiki updated",
+	"gollum.created_page":                           "[Created] <%s|%s>",
+	"gollum.edited_page":                            "[Edited] <%s|%s> (<%s/_compare/%s|diff>)",
+	"issue_comment.created":                         "Commented",
+	"issue_comment.deleted":                         "Comment deleted",
+	"issue_comment.edited":                          "Comment edited",
+	"issues.assigned":                               "Issue assigned",
+	"issues.closed":                                 "Issue closed",
+	"issues.deleted":                                "Issue deleted",
+	"issues.demilestoned":                           "Milestone removed",
+	"issues.edited":                                 "Issue edited",
+	"issues.labeled":                                "Label added to issue",
+	"issues.locked":                                 "Issue locked",
+	"issues.milestoned":                             "Milestone set",
+	"issues.opened":                                 "Issue opened",
+	"issues.pinned":                                 "Issue pinned",
+	"issues.reopened":                               "Issue reopened",
+	"issues.transferred":                            "Issue transferred",
+	"issues.unassigned":                             "Issue unassigned",
+	"issues.unlabeled":                              "Label removed from issue",
+	"issues.unlocked":                               "Issue unlocked",
+	"issues.unpinned":                               "Issue unpinned",
+	"label.created":                                 "Label created",
+	"label.deleted":                                 "Label deleted",
+	"label.edited":                                  "Label edited",
+	"marketplace_purchase.cancelled":                "Marketplace plan cancelled",
+	"marketplace_purchase.changed":                  "Marketplace plan changed",
+	"marketplace_purchase.pending_change":           "Marketplace plan change scheduled",
+	"marketplace_purchase.pending_change_cancelled": "Scheduled Marketplace plan change cancelled",
+	"marketplace_purchase.purchased":                "Marketplace plan purchased",
+	"member.added":                                  "Collaborator added",
+	"member.edited":                                 "Collaborator permission changed",
+	"member.removed":                                "Collaborator removed",
+	"membership.added":                              "Member added to team",
+	"membership.removed":                            "Member removed from team",
+	"merge_group.checks_requested":                  "Merge queue checks requested",
+	"merge_group.dequeued":                          "Removed from merge queue",
+	"merge_group.destroyed":                         "Merge queue group destroyed",
+	"merge_group.invalidated":                       "Merge queue group invalidated",
+	"merge_group.merged":                            "Merged from merge queue",
+	"milestone.closed":                              "Milestone closed",
+	"milestone.created":                             "Milestone created",
+	"milestone.deleted":                             "Milestone deleted",
+	"milestone.edited":                              "Milestone edited",
+	"milestone.opened":                              "Milestone reopened",
+	"package.deleted":                               "Package deleted",
+	"package.published":                             "Package published",
+	"package.updated":                               "Package updated",
+	"page_build.built":                              "GitHub Pages built",
+	"page_build.errored":                            "GitHub Pages build failed",
+	"ping":                                          "WebHook connected",
+	"ping.all_events":                               "* (all events)",
+	"ping.unrendered":                               "The following events are not notified: %s",
+	"projects_v2_item.archived":                     "Project item archived",
+	"projects_v2_item.converted":                    "Draft converted to issue",
+	"projects_v2_item.created":                      "Item added to project",
+	"projects_v2_item.deleted":                      "Item removed from project",
+	"projects_v2_item.edited":                       "Project item updated",
+	"projects_v2_item.reordered":                    "Project item reordered",
+	"projects_v2_item.restored":                     "Project item restored",
+	"pull_request.assigned":                         "PR assigned",
+	"pull_request.closed":                           "PR closed",
+	"pull_request.converted_to_draft":               "PR converted to draft",
+	"pull_request.edited":                           "PR edited",
+	"pull_request.labeled":                          "Label added to PR",
+	"pull_request.locked":                           "PR locked",
+	"pull_request.opened":                           "PR opened",
+	"pull_request.ready_for_review":                 "PR ready for review",
+	"pull_request.reopened":                         "PR reopened",
+	"pull_request.review_request_removed":           "PR review request removed",
+	"pull_request.review_requested":                 "PR review requested",
+	"pull_request.synchronize":                      "Commits pushed to PR",
+	"pull_request.unassigned":                       "PR unassigned",
+	"pull_request.unlabeled":                        "Label removed from PR",
+	"pull_request.unlocked":                         "PR unlocked",
+	"pull_request_review.approved":                  ":white_check_mark: PR approved",
+	"pull_request_review.changes_requested":         ":x: Changes requested on PR",
+	"pull_request_review.commented":                 ":speech_balloon: PR reviewed with comments",
+	"pull_request_review.dismissed":                 ":no_entry_sign: PR review dismissed",
+	"pull_request_review.submitted":                 "PR reviewed",
+	"pull_request_review_comment.created":           "Commented on PR",
+	"pull_request_review_comment.deleted":           "PR comment deleted",
+	"pull_request_review_comment.edited":            "PR comment edited",
+	"push":                                          "Pushed",
+	"push.forced":                                   ":warning: Force pushed",
+	"push.more_commits":                             "<%s|…and %d more commits>\n",
+	"quiet_hours.queued":                            "%d notifications arrived during quiet hours",
+	"repository.archived":                           "Repository archived",
+	"repository.created":                            "Repository created",
+	"repository.deleted":                            "Repository deleted",
+	"repository.edited":                             "Repository settings changed",
+	"repository.privatized":                         "Repository made private",
+	"repository.publicized":                         "Repository made public",
+	"repository.renamed":                            "Repository renamed",
+	"repository.transferred":                        "Repository transferred",
+	"repository.unarchived":                         "Repository unarchived",
+	"repository_ruleset.created":                    "Ruleset created",
+	"repository_ruleset.deleted":                    "Ruleset deleted",
+	"repository_ruleset.edited":                     "Ruleset edited",
+	"security_alert.code_scanning_alert":            "Code scanning alert",
+	"security_alert.dependabot_alert":               "Dependabot alert",
+	"security_alert.repository_vulnerability_alert": "Vulnerability alert",
+	"security_alert.secret_scanning_alert":          "Secret scanning alert",
+	"security_alert.security_advisory":              "Security advisory",
+	"sponsorship.cancelled":                         "Sponsorship cancelled",
+	"sponsorship.created":                           "New sponsor",
+	"sponsorship.edited":                            "Sponsorship edited",
+	"sponsorship.pending_cancellation":              "Sponsorship cancellation scheduled",
+	"sponsorship.pending_tier_change":               "Sponsorship tier change scheduled",
+	"sponsorship.tier_changed":                      "Sponsorship tier changed",
+	"star.batch":                                    "%d stars today",
+	"star.batch_rest":                               "Stars on %s: %d",
+	"star.created":                                  "Starred",
+	"star.deleted":                                  "Star removed",
+	"star.milestone":                                ":tada: Reached %d stars",
+	"state.draft":                                   "Draft",
+	"team.created":                                  "Team created",
+	"team.deleted":                                  "Team deleted",
+	"team.edited":                                   "Team edited",
+	"team.removed_from_repository":                  "Repository removed from team",
+	"team_add":                                      "Repository added to team",
+	"value.empty":                                   "(none)",
+	"value.none":                                    "None",
+}
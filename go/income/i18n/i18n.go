@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	DefaultLocale = "ja"
+)
+
+// key から表示する文字列 (fmt の書式を含む場合がある)
+type Catalog map[string]string
+
+var catalogs = map[string]Catalog{
+	"en": en,
+	"ja": ja,
+}
+
+// Configure で設定する言語 (起動時に一度だけ設定する)
+var (
+	defaultLocale string
+	routeLocales map[string]string
+)
+
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// 通知の言語と route ごとの言語を設定する
+func Configure(locale string, routes map[string]string) {
+	defaultLocale = locale
+	routeLocales = map[string]string{}
+	for route, l := range routes {
+		routeLocales[strings.ToLower(route)] = l
+	}
+}
+
+// route の言語、未設定の場合は Configure で設定した言語 (さらに未設定の場合は DefaultLocale)
+func RouteLocale(route string) string {
+	if locale := routeLocales[strings.ToLower(route)]; route != "" && locale != "" {
+		return locale
+	}
+	if defaultLocale != "" {
+		return defaultLocale
+	}
+	return DefaultLocale
+}
+
+type Localizer struct {
+	catalog Catalog
+}
+
+// 対応していない言語の場合は DefaultLocale を使う
+func NewLocalizer(locale string) *Localizer {
+	c, ok := catalogs[locale]
+	if !ok {
+		c = catalogs[DefaultLocale]
+	}
+	return &Localizer{catalog: c}
+}
+
+// args がある場合は fmt.Sprintf で埋め込む (key が無い場合は key をそのまま返す)
+func (l *Localizer) T(key string, args ...interface{}) string {
+	s, ok := l.catalog[key]
+	if !ok {
+		s = key
+	}
+	if len(args) == 0 {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
@@ -0,0 +1,74 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var verb = regexp.MustCompile(`%[a-z%]`)
+
+// すべての言語が同じ key と書式を持つ
+func TestCatalogs(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	base := catalogs[DefaultLocale]
+	for locale, c := range catalogs {
+		for key, s := range base {
+			v, ok := c[key]
+			if !assert.True(ok, "%s: missing key %s", locale, key) {
+				continue
+			}
+			assert.Equal(verb.FindAllString(s, -1), verb.FindAllString(v, -1), "%s: format of %s", locale, key)
+		}
+		for key := range c {
+			_, ok := base[key]
+			assert.True(ok, "%s: unknown key %s", locale, key)
+		}
+	}
+}
+
+func TestLocalizerT(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	ja := NewLocalizer("ja")
+	assert.Equal(ja.T("field.account"), "アカウント")
+	assert.Equal(ja.T("star.batch", 3), "本日 3 件のスターが付きました")
+	assert.Equal(ja.T("unknown.key"), "unknown.key")
+
+	en := NewLocalizer("en")
+	assert.Equal(en.T("field.account"), "Account")
+	assert.Equal(en.T("star.batch", 3), "3 stars today")
+
+	// 対応していない言語
+	assert.Equal(NewLocalizer("fr").T("field.account"), "アカウント")
+}
+
+func TestRouteLocale(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("default", func(t *testing.T) {
+		Configure("", nil)
+		assert.Equal(RouteLocale(""), DefaultLocale)
+	})
+
+	t.Run("deployment", func(t *testing.T) {
+		Configure("en", nil)
+		assert.Equal(RouteLocale(""), "en")
+		assert.Equal(RouteLocale("audit"), "en")
+	})
+
+	t.Run("route", func(t *testing.T) {
+		Configure("en", map[string]string{"AUDIT": "ja"})
+		assert.Equal(RouteLocale("audit"), "ja")
+		assert.Equal(RouteLocale("sponsor"), "en")
+		assert.Equal(RouteLocale(""), "en")
+	})
+
+	t.Cleanup(func(){
+		Configure("", nil)
+	})
+}
@@ -0,0 +1,262 @@
+package i18n
+
+// 日本語 (デフォルト)
+var ja = Catalog{
+	"branch_protection_rule.allow_deletions":        "ブランチ削除: %s\n",
+	"branch_protection_rule.allow_force_pushes":     "Force Push: %s\n",
+	"branch_protection_rule.code_owner_review":      "コードオーナーのレビュー: %t\n",
+	"branch_protection_rule.created":                "ブランチ保護ルールが作成されました",
+	"branch_protection_rule.deleted":                "ブランチ保護ルールが削除されました",
+	"branch_protection_rule.edited":                 "ブランチ保護ルールが変更されました",
+	"branch_protection_rule.enforce_admins":         "管理者への適用: %t\n",
+	"branch_protection_rule.required_reviews":       "必須レビュー数: %d\n",
+	"branch_protection_rule.required_status_checks": "必須ステータスチェック: %s\n",
+	"commit_comment.created":                        "コメントされました",
+	"config.reload_failed":                          "設定の再読み込みに失敗しました (以前の設定で通知します)",
+	"config.reloaded":                               "設定を再読み込みしました",
+	"create.branch":                                 "ブランチが作成されました",
+	"create.tag":                                    "タグが作成されました",
+	"delete.branch":                                 "ブランチが削除されました",
+	"delete.tag":                                    "タグが削除されました",
+	"deployment_status.failure":                     "デプロイに失敗しました",
+	"deployment_status.success":                     "デプロイが完了しました",
+	"digest.more":                                   "…他 %d 件",
+	"digest.period":                                 "%s 〜 %s (%d 件)",
+	"field.account":                                 "アカウント",
+	"field.action":                                  "アクション",
+	"field.alert":                                   "アラート",
+	"field.author":                                  "作成者",
+	"field.before":                                  "変更前",
+	"field.before_deletion":                         "削除前",
+	"field.body":                                    "内容",
+	"field.body_from":                               "内容(変更前)",
+	"field.body_to":                                 "内容(変更後)",
+	"field.branch":                                  "ブランチ",
+	"field.branch_name":                             "ブランチ名",
+	"field.changes":                                 "変更",
+	"field.closed_issues":                           "Issue (クローズ)",
+	"field.color":                                   "色",
+	"field.color_from":                              "色(変更前)",
+	"field.color_to":                                "色(変更後)",
+	"field.comment":                                 "コメント",
+	"field.comment_from":                            "コメント(変更前)",
+	"field.comment_to":                              "コメント(変更後)",
+	"field.commit":                                  "Commit",
+	"field.commit_id":                               "CommitID",
+	"field.content_type":                            "種類",
+	"field.description":                             "説明",
+	"field.description_from":                        "説明(変更前)",
+	"field.description_to":                          "説明(変更後)",
+	"field.digest":                                  "ダイジェスト",
+	"field.due_on":                                  "期限",
+	"field.due_on_from":                             "期限(変更前)",
+	"field.due_on_to":                               "期限(変更後)",
+	"field.ecosystem":                               "種類",
+	"field.effective_date":                          "適用日",
+	"field.enforcement":                             "適用状態",
+	"field.environment":                             "環境",
+	"field.error":                                   "エラー",
+	"field.events":                                  "Event",
+	"field.field":                                   "フィールド",
+	"field.fork_to":                                 "フォーク先",
+	"field.forks":                                   "フォーク数",
+	"field.free_trial":                              "無料トライアル",
+	"field.hook_id":                                 "Hook ID",
+	"field.inline_comments":                         "インラインコメント",
+	"field.issues_closed":                           "Issue (クローズ)",
+	"field.issues_opened":                           "Issue (オープン)",
+	"field.label":                                   "ラベル",
+	"field.label_from":                              "ラベル(変更前)",
+	"field.label_to":                                "ラベル(変更後)",
+	"field.link":                                    "リンク",
+	"field.link_from":                               "リンク(譲渡前)",
+	"field.link_to":                                 "リンク(譲渡後)",
+	"field.milestone":                               "マイルストーン",
+	"field.milestone_from":                          "マイルストーン(変更前)",
+	"field.milestone_to":                            "マイルストーン(変更後)",
+	"field.open_issues":                             "Issue (オープン)",
+	"field.organization":                            "Organization",
+	"field.other_events":                            "その他の Event",
+	"field.owner_from":                              "オーナー(変更前)",
+	"field.owner_to":                                "オーナー(変更後)",
+	"field.package":                                 "パッケージ",
+	"field.pages":                                   "ページ",
+	"field.period":                                  "期間",
+	"field.permission":                              "権限",
+	"field.permission_from":                         "権限(変更前)",
+	"field.permission_to":                           "権限(変更後)",
+	"field.plan":                                    "プラン",
+	"field.privacy":                                 "公開範囲",
+	"field.privacy_from":                            "公開範囲(変更前)",
+	"field.privacy_to":                              "公開範囲(変更後)",
+	"field.progress":                                "進捗",
+	"field.pull_request":                            "PR",
+	"field.pull_requests_merged":                    "PR (マージ)",
+	"field.pull_requests_opened":                    "PR (オープン)",
+	"field.quiet_hours":                             "通知を控える時間帯",
+	"field.reason":                                  "理由",
+	"field.ref":                                     "Ref",
+	"field.releases":                                "リリース",
+	"field.repository":                              "リポジトリ",
+	"field.repository_name_from":                    "リポジトリ名(変更前)",
+	"field.repository_name_to":                      "リポジトリ名(変更後)",
+	"field.reviewers":                               "レビュアー",
+	"field.rules":                                   "ルール",
+	"field.ruleset":                                 "ルールセット",
+	"field.settings":                                "設定",
+	"field.settings_from":                           "設定(変更前)",
+	"field.settings_to":                             "設定(変更後)",
+	"field.severity":                                "重要度",
+	"field.sponsor":                                 "スポンサー",
+	"field.stars":                                   "スター数",
+	"field.state":                                   "状態",
+	"field.tag_name":                                "タグ名",
+	"field.target":                                  "対象",
+	"field.target_branch":                           "対象ブランチ",
+	"field.target_user":                             "対象者",
+	"field.team":                                    "チーム",
+	"field.team_name_from":                          "チーム名(変更前)",
+	"field.team_name_to":                            "チーム名(変更後)",
+	"field.tier":                                    "ティア",
+	"field.title":                                   "タイトル",
+	"field.title_from":                              "タイトル(変更前)",
+	"field.title_to":                                "タイトル(変更後)",
+	"field.top_contributors":                        "コントリビューター",
+	"field.transferred_to":                          "譲渡先",
+	"field.uploader":                                "アップロード",
+	"field.value_from":                              "値(変更前)",
+	"field.value_to":                                "値(変更後)",
+	"field.version":                                 "バージョン",
+	"field.warning":                                 "警告",
+	"fork":                                          "フォークされました",
+	"format.change":                                 "%s → %s",
+	"format.count":                                  "%d 件",
+	"format.one_time":                               "%s / 1 回",
+	"format.per_month":                              "%s / 月",
+	"format.per_year":                               "%s / 年",
+	"format.units":                                  " × %d %s",
+	"format.until":                                  "%s まで",
+	"gollum":                                        "Wiki が更新されました",
+	"gollum.created_page":                           "[作成] <%s|%s>",
+	"gollum.edited_page":                            "[編集] <%s|%s> (<%s/_compare/%s|差分>)",
+	"issue_comment.created":                         "コメントされました",
+	"issue_comment.deleted":                         "コメントが削除されました",
+	"issue_comment.edited":                          "コメントが変更されました",
+	"issues.assigned":                               "Issue にアサインされました",
+	"issues.closed":                                 "Issue がクローズされました",
+	"issues.deleted":                                "Issue が削除されました",
+	"issues.demilestoned":                           "マイルストーンが解除されました",
+	"issues.edited":                                 "Issue が編集されました",
+	"issues.labeled":                                "Issue にラベルが付与されました",
+	"issues.locked":                                 "Issue がロックされました",
+	"issues.milestoned":                             "マイルストーンが設定されました",
+	"issues.opened":                                 "Issue がオープンされました",
+	"issues.pinned":                                 "Issue がピン留めされました",
+	"issues.reopened":                               "Issue が再オープンされました",
+	"issues.transferred":                            "Issue が譲渡されました",
+	"issues.unassigned":                             "Issue にアンアサインされました",
+	"issues.unlabeled":                              "Issue のラベルが外されました",
+	"issues.unlocked":                               "Issue のロックが解除されました",
+	"issues.unpinned":                               "Issue のピン留めが解除されました",
+	"label.created":                                 "ラベルが作成されました",
+	"label.deleted":                                 "ラベルが削除されました",
+	"label.edited":                                  "ラベルが編集されました",
+	"marketplace_purchase.cancelled":                "Marketplace のプランが解約されました",
+	"marketplace_purchase.changed":                  "Marketplace のプランが変更されました",
+	"marketplace_purchase.pending_change":           "Marketplace のプランの変更が予定されました",
+	"marketplace_purchase.pending_change_cancelled": "Marketplace のプランの変更が取り消されました",
+	"marketplace_purchase.purchased":                "Marketplace のプランが購入されました",
+	"member.added":                                  "コラボレーターが追加されました",
+	"member.edited":                                 "コラボレーターの権限が変更されました",
+	"member.removed":                                "コラボレーターが削除されました",
+	"membership.added":                              "チームにメンバーが追加されました",
+	"membership.removed":                            "チームからメンバーが削除されました",
+	"merge_group.checks_requested":                  "マージキューのチェックが開始されました",
+	"merge_group.dequeued":                          "マージキューから外されました",
+	"merge_group.destroyed":                         "マージキューのグループが削除されました",
+	"merge_group.invalidated":                       "マージキューのグループが無効になりました",
+	"merge_group.merged":                            "マージキューからマージされました",
+	"milestone.closed":                              "マイルストーンがクローズされました",
+	"milestone.created":                             "マイルストーンが作成されました",
+	"milestone.deleted":                             "マイルストーンが削除されました",
+	"milestone.edited":                              "マイルストーンが編集されました",
+	"milestone.opened":                              "マイルストーンが再オープンされました",
+	"package.deleted":                               "パッケージが削除されました",
+	"package.published":                             "パッケージが公開されました",
+	"package.updated":                               "パッケージが更新されました",
+	"page_build.built":                              "GitHub Pages がビルドされました",
+	"page_build.errored":                            "GitHub Pages のビルドに失敗しました",
+	"ping":                                          "WebHook が接続されました",
+	"ping.all_events":                               "* (すべての Event)",
+	"ping.unrendered":                               "以下の Event は通知されません: %s",
+	"projects_v2_item.archived":                     "プロジェクトのアイテムがアーカイブされました",
+	"projects_v2_item.converted":                    "ドラフトが Issue に変換されました",
+	"projects_v2_item.created":                      "プロジェクトにアイテムが追加されました",
+	"projects_v2_item.deleted":                      "プロジェクトからアイテムが削除されました",
+	"projects_v2_item.edited":                       "プロジェクトのアイテムが更新されました",
+	"projects_v2_item.reordered":                    "プロジェクトのアイテムが並び替えられました",
+	"projects_v2_item.restored":                     "プロジェクトのアイテムが復元されました",
+	"pull_request.assigned":                         "PR にアサインされました",
+	"pull_request.closed":                           "PR がクローズされました",
+	"pull_request.converted_to_draft":               "PR が Draft に変更されました",
+	"pull_request.edited":                           "PR が編集されました",
+	"pull_request.labeled":                          "PR にラベルが付与されました",
+	"pull_request.locked":                           "PR がロックされました",
+	"pull_request.opened":                           "PR がオープンされました",
+	"pull_request.ready_for_review":                 "PR の準備が整いました",
+	"pull_request.reopened":                         "PR が再オープンされました",
+	"pull_request.review_request_removed":           "PR のレビュー要求が取下げされました",
+	"pull_request.review_requested":                 "PR のレビューをお願いされました",
+	"pull_request.synchronize":                      "PR にコミットがプッシュされました",
+	"pull_request.unassigned":                       "PR にアンアサインされました",
+	"pull_request.unlabeled":                        "PR のラベルが外されました",
+	"pull_request.unlocked":                         "PR のロックが解除されました",
+	"pull_request_review.approved":                  ":white_check_mark: PR が承認されました",
+	"pull_request_review.changes_requested":         ":x: PR に変更が要求されました",
+	"pull_request_review.commented":                 ":speech_balloon: PR にレビューコメントされました",
+	"pull_request_review.dismissed":                 ":no_entry_sign: PR のレビューが却下されました",
+	"pull_request_review.submitted":                 "PR のレビューがされました",
+	"pull_request_review_comment.created":           "PR にコメントされました",
+	"pull_request_review_comment.deleted":           "PR のコメントが削除されました",
+	"pull_request_review_comment.edited":            "PR のコメントが変更されました",
+	"push":                                          "プッシュされました",
+	"push.forced":                                   ":warning: Force Push されました",
+	"push.more_commits":                             "<%s|…他 %d 件のコミット>\n",
+	"quiet_hours.queued":                            "通知を控えていた間に %d 件の通知がありました",
+	"repository.archived":                           "リポジトリがアーカイブされました",
+	"repository.created":                            "リポジトリが作成されました",
+	"repository.deleted":                            "リポジトリが削除されました",
+	"repository.edited":                             "リポジトリの設定が変更されました",
+	"repository.privatized":                         "リポジトリが非公開になりました",
+	"repository.publicized":                         "リポジトリが公開されました",
+	"repository.renamed":                            "リポジトリ名が変更されました",
+	"repository.transferred":                        "リポジトリが譲渡されました",
+	"repository.unarchived":                         "リポジトリのアーカイブが解除されました",
+	"repository_ruleset.created":                    "ルールセットが作成されました",
+	"repository_ruleset.deleted":                    "ルールセットが削除されました",
+	"repository_ruleset.edited":                     "ルールセットが変更されました",
+	"security_alert.code_scanning_alert":            "Code scanning のアラート",
+	"security_alert.dependabot_alert":               "Dependabot のアラート",
+	"security_alert.repository_vulnerability_alert": "脆弱性のアラート",
+	"security_alert.secret_scanning_alert":          "Secret scanning のアラート",
+	"security_alert.security_advisory":              "セキュリティアドバイザリ",
+	"sponsorship.cancelled":                         "スポンサーが終了しました",
+	"sponsorship.created":                           "スポンサーになりました",
+	"sponsorship.edited":                            "スポンサー情報が変更されました",
+	"sponsorship.pending_cancellation":              "スポンサーの終了が予定されました",
+	"sponsorship.pending_tier_change":               "ティアの変更が予定されました",
+	"sponsorship.tier_changed":                      "ティアが変更されました",
+	"star.batch":                                    "本日 %d 件のスターが付きました",
+	"star.batch_rest":                               "%s に %d 件のスターが付きました",
+	"star.created":                                  "スターが付けられました",
+	"star.deleted":                                  "スターが外されました",
+	"star.milestone":                                ":tada: スター数が %d に到達しました",
+	"state.draft":                                   "Draft",
+	"team.created":                                  "チームが作成されました",
+	"team.deleted":                                  "チームが削除されました",
+	"team.edited":                                   "チームが編集されました",
+	"team.removed_from_repository":                  "チームからリポジトリが削除されました",
+	"team_add":                                      "チームにリポジトリが追加されました",
+	"value.empty":                                   "(なし)",
+	"value.none":                                    "なし",
+}
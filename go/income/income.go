@@ -3,6 +3,7 @@ package income
 import (
 	"bytes"
 
+	"github.com/SongCastle/ggnb/income/builder"
 	"github.com/SongCastle/ggnb/income/message"
 )
 
@@ -16,6 +17,9 @@ type AbstractManager interface {
 	Init(message message.AbstractMessage)
 	BuildMessage(headers, body interface{}) (*bytes.Buffer, error)
 	BuildDummyMessage() (*bytes.Buffer, error)
+	Route() string
+	Urgent() bool
+	Digest() *builder.DigestEntry
 }
 
 type Manager struct {
@@ -36,3 +40,15 @@ func (m *Manager) BuildMessage(headers, body interface{}) (*bytes.Buffer, error)
 func (m *Manager) BuildDummyMessage() (*bytes.Buffer, error) {
 	return m.message.ToDummyPayload()
 }
+
+func (m *Manager) Route() string {
+	return m.message.Route()
+}
+
+func (m *Manager) Urgent() bool {
+	return m.message.Urgent()
+}
+
+func (m *Manager) Digest() *builder.DigestEntry {
+	return m.message.Digest()
+}
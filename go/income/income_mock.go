@@ -3,6 +3,7 @@ package income
 import (
 	"bytes"
 
+	"github.com/SongCastle/ggnb/income/builder"
 	"github.com/SongCastle/ggnb/income/message"
 	"github.com/stretchr/testify/mock"
 )
@@ -23,3 +24,19 @@ func (im *MockedIncomeManager) BuildDummyMessage() (*bytes.Buffer, error) {
 	args := im.Called()
 	return args[0].(*bytes.Buffer), args.Error(1)
 }
+
+func (im *MockedIncomeManager) Route() string {
+	args := im.Called()
+	return args.String(0)
+}
+
+func (im *MockedIncomeManager) Urgent() bool {
+	args := im.Called()
+	return args.Bool(0)
+}
+
+func (im *MockedIncomeManager) Digest() *builder.DigestEntry {
+	args := im.Called()
+	e, _ := args[0].(*builder.DigestEntry)
+	return e
+}
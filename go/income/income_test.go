@@ -71,6 +71,16 @@ func TestManagerBuildMessage(t *testing.T) {
 	})
 }
 
+func TestManagerRoute(t *testing.T) {
+	t.Parallel()
+
+	mm := &message.MockedMessage{}
+	mm.On("Route").Return("audit")
+
+	m := &Manager{message: mm}
+	assert.Equal(t, m.Route(), "audit")
+}
+
 func TestBuildDummyMessage(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
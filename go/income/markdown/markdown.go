@@ -0,0 +1,280 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Slack の mrkdwn (デフォルト)
+	Mrkdwn = "mrkdwn"
+	// 装飾を除いたテキスト
+	Plain = "plain"
+	// Discord や Teams 向けの Markdown (GitHub 固有の記法のみ変換する)
+	Markdown = "markdown"
+	// 変換しない
+	Raw = "raw"
+)
+
+var (
+	htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+	htmlTag = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	// <https://example.com> (HTML タグとして除かない)
+	autolink = regexp.MustCompile(`<[a-zA-Z][a-zA-Z0-9+.\-]*://[^\s<>]+>`)
+	fence = regexp.MustCompile("^\\s*(```|~~~)")
+	heading = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)\s*#*\s*$`)
+	taskItem = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	listItem = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	quote = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	tableSeparator = regexp.MustCompile(`^\s*\|?(\s*:?-+:?\s*\|)+\s*:?-*:?\s*$`)
+	tableRow = regexp.MustCompile(`^\s*\|(.*)\|\s*$`)
+	image = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	link = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	bold = regexp.MustCompile(`\*\*([^*\n]+)\*\*|__([^_\n]+)__`)
+	// 前後の文字は replaceItalic で確認する (隣り合う斜体を読み飛ばさないよう、正規表現では消費しない)
+	italic = regexp.MustCompile(`\*([^*\s][^*\n]*?)\*`)
+	strike = regexp.MustCompile(`~~([^~\n]+)~~`)
+	inlineCode = regexp.MustCompile("`[^`\n]+`")
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+func Validate(format string) error {
+	switch format {
+	case "", Mrkdwn, Plain, Markdown, Raw:
+		return nil
+	}
+	return errors.New(fmt.Sprintf("Invalid format: %q", format))
+}
+
+// GitHub Flavored Markdown を format に変換する (未指定の場合は Mrkdwn)
+func Convert(s, format string) string {
+	if format == Raw || s == "" {
+		return s
+	}
+	if format == "" {
+		format = Mrkdwn
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = htmlComment.ReplaceAllString(s, "")
+
+	var out []convertedLine
+	// コードブロックを開始した記号 (``` もしくは ~~~、コードブロックの外では空)
+	inCode := ""
+	for _, line := range strings.Split(s, "\n") {
+		if m := fence.FindStringSubmatch(line); m != nil && (inCode == "" || inCode == m[1]) {
+			if inCode == "" {
+				inCode = m[1]
+			} else {
+				inCode = ""
+			}
+			switch format {
+			case Plain:
+				continue
+			// mrkdwn は ``` のみ対応し、コードブロックの言語指定にも対応していない
+			case Mrkdwn:
+				out = append(out, convertedLine{text: "```"})
+			default:
+				out = append(out, convertedLine{text: m[1]})
+			}
+			continue
+		}
+		if inCode != "" {
+			if format == Mrkdwn {
+				line = escape(line)
+			}
+			out = append(out, convertedLine{text: line})
+			continue
+		}
+		if m := heading.FindStringSubmatch(line); m != nil {
+			out = append(out, convertedLine{text: convertHeading(m[1], format), heading: true})
+			continue
+		}
+		if converted, ok := convertLine(line, format); ok {
+			out = append(out, convertedLine{text: converted})
+		}
+	}
+	return collapse(out)
+}
+
+type convertedLine struct {
+	text string
+	heading bool
+}
+
+func convertHeading(s, format string) string {
+	text := convertInline(stripHTML(s), format)
+	switch format {
+	case Mrkdwn:
+		return fmt.Sprintf("*%s*", text)
+	case Markdown:
+		return fmt.Sprintf("**%s**", text)
+	}
+	return text
+}
+
+func stripHTML(s string) string {
+	s = htmlBreak.ReplaceAllString(s, " ")
+	return htmlTag.ReplaceAllStringFunc(s, func(tag string) string {
+		if autolink.FindString(tag) == tag {
+			return tag
+		}
+		return ""
+	})
+}
+
+// 表の区切り行等、出力しない行は false を返す
+func convertLine(line, format string) (string, bool) {
+	if strings.Contains(line, "|") && tableSeparator.MatchString(line) {
+		return "", false
+	}
+	line = stripHTML(line)
+
+	if m := taskItem.FindStringSubmatch(line); m != nil {
+		box := "☐"
+		if m[2] != " " {
+			box = "☑"
+		}
+		return fmt.Sprintf("%s%s %s", m[1], box, convertInline(m[3], format)), true
+	}
+	if m := quote.FindStringSubmatch(line); m != nil {
+		if format == Plain {
+			return convertInline(m[1], format), true
+		}
+		return "> " + convertInline(m[1], format), true
+	}
+	if m := tableRow.FindStringSubmatch(line); m != nil {
+		cells := strings.Split(m[1], "|")
+		for i, c := range cells {
+			cells[i] = convertInline(strings.TrimSpace(c), format)
+		}
+		return strings.Join(cells, " | "), true
+	}
+	if m := listItem.FindStringSubmatch(line); m != nil && format != Markdown {
+		return fmt.Sprintf("%s• %s", m[1], convertInline(m[2], format)), true
+	}
+	return convertInline(line, format), true
+}
+
+// インラインコード以外の装飾とリンクを変換する
+func convertInline(s, format string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range inlineCode.FindAllStringIndex(s, -1) {
+		b.WriteString(convertText(s[last:loc[0]], format))
+		code := s[loc[0]:loc[1]]
+		switch format {
+		case Plain:
+			code = code[1 : len(code)-1]
+		case Mrkdwn:
+			code = escape(code)
+		}
+		b.WriteString(code)
+		last = loc[1]
+	}
+	b.WriteString(convertText(s[last:], format))
+	return b.String()
+}
+
+// 自動リンクはエスケープ・装飾の変換をせずに出力する
+func convertText(s, format string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range autolink.FindAllStringIndex(s, -1) {
+		b.WriteString(convertDecoration(s[last:loc[0]], format))
+		url := s[loc[0]+1 : loc[1]-1]
+		switch format {
+		case Mrkdwn:
+			b.WriteString("<" + strings.ReplaceAll(url, "&", "&amp;") + ">")
+		case Plain:
+			b.WriteString(url)
+		default:
+			b.WriteString(s[loc[0]:loc[1]])
+		}
+		last = loc[1]
+	}
+	b.WriteString(convertDecoration(s[last:], format))
+	return b.String()
+}
+
+func convertDecoration(s, format string) string {
+	switch format {
+	case Mrkdwn:
+		s = escape(s)
+		s = image.ReplaceAllString(s, "<$2|$1>")
+		s = link.ReplaceAllString(s, "<$2|$1>")
+		// 太字 (*) と斜体 (_) が衝突しないよう、斜体を先に変換する
+		s = replaceItalic(s, "_")
+		s = bold.ReplaceAllString(s, "*$1$2*")
+		s = strike.ReplaceAllString(s, "~$1~")
+	case Plain:
+		s = image.ReplaceAllString(s, "$1 ($2)")
+		s = link.ReplaceAllString(s, "$1 ($2)")
+		s = bold.ReplaceAllString(s, "$1$2")
+		s = replaceItalic(s, "")
+		s = strike.ReplaceAllString(s, "$1")
+	}
+	return s
+}
+
+// 前後が * や英数字でない *text* を mark で囲む
+func replaceItalic(s, mark string) string {
+	var b strings.Builder
+	pos := 0
+	for {
+		loc := italic.FindStringSubmatchIndex(s[pos:])
+		if loc == nil {
+			break
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		if (start > 0 && italicBoundary(s[start-1])) || (end < len(s) && italicBoundary(s[end])) {
+			b.WriteString(s[pos : start+1])
+			pos = start + 1
+			continue
+		}
+		b.WriteString(s[pos:start])
+		b.WriteString(mark + s[pos+loc[2]:pos+loc[3]] + mark)
+		pos = end
+	}
+	b.WriteString(s[pos:])
+	return b.String()
+}
+
+// 正規表現の [*\w] (ASCII の英数字・_) と同じ
+func italicBoundary(c byte) bool {
+	return c == '*' || c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// Slack の mrkdwn で制御文字として扱われる文字をエスケープする
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// PR テンプレート等の内容が空の見出しを除き、連続する空行をまとめる
+func collapse(lines []convertedLine) string {
+	var out []string
+	for i, line := range lines {
+		if line.heading && emptySection(lines[i+1:]) {
+			continue
+		}
+		out = append(out, line.text)
+	}
+	s := strings.Join(out, "\n")
+	s = blankLines.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
+
+// 次の見出しまで (もしくは最後まで) 空行のみ
+func emptySection(lines []convertedLine) bool {
+	for _, line := range lines {
+		if line.heading {
+			return true
+		}
+		if strings.TrimSpace(line.text) != "" {
+			return false
+		}
+	}
+	return true
+}
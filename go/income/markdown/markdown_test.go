@@ -0,0 +1,76 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, format := range []string{"", Mrkdwn, Plain, Markdown, Raw} {
+		assert.Nil(Validate(format))
+	}
+	assert.EqualError(Validate("html"), `Invalid format: "html"`)
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	t.Run("inline", func(t *testing.T) {
+		s := "**bold** *italic* ~~strike~~ [link](https://example.com) `**code**`"
+		assert.Equal(Convert(s, Mrkdwn), "*bold* _italic_ ~strike~ <https://example.com|link> `**code**`")
+		assert.Equal(Convert(s, Plain), "bold italic strike link (https://example.com) **code**")
+		assert.Equal(Convert(s, Markdown), s)
+		assert.Equal(Convert(s, Raw), s)
+		assert.Equal(Convert(s, ""), Convert(s, Mrkdwn))
+	})
+
+	// 隣り合う斜体もそれぞれ変換する
+	t.Run("adjacent italic", func(t *testing.T) {
+		assert.Equal(Convert("*a* *b* 2*3*4 **c**", Mrkdwn), "_a_ _b_ 2*3*4 *c*")
+		assert.Equal(Convert("*a* *b*", Plain), "a b")
+	})
+
+	t.Run("autolink", func(t *testing.T) {
+		s := "See <https://example.com/a?b=1&c=2> and <b>*this*</b>"
+		assert.Equal(Convert(s, Mrkdwn), "See <https://example.com/a?b=1&amp;c=2> and _this_")
+		assert.Equal(Convert(s, Plain), "See https://example.com/a?b=1&c=2 and this")
+		assert.Equal(Convert(s, Markdown), "See <https://example.com/a?b=1&c=2> and *this*")
+	})
+
+	t.Run("escape", func(t *testing.T) {
+		assert.Equal(Convert("a < b && c > d", Mrkdwn), "a &lt; b &amp;&amp; c &gt; d")
+		assert.Equal(Convert("a < b", Plain), "a < b")
+	})
+
+	t.Run("blocks", func(t *testing.T) {
+		s := "# Title\n- [ ] todo\n- [x] done\n* item\n> quote\n\n| a | b |\n|---|:-:|\n| 1 | 2 |"
+		assert.Equal(Convert(s, Mrkdwn), "*Title*\n☐ todo\n☑ done\n• item\n> quote\n\na | b\n1 | 2")
+		assert.Equal(Convert(s, Plain), "Title\n☐ todo\n☑ done\n• item\nquote\n\na | b\n1 | 2")
+		assert.Equal(Convert(s, Markdown), "**Title**\n☐ todo\n☑ done\n* item\n> quote\n\na | b\n1 | 2")
+	})
+
+	t.Run("code block", func(t *testing.T) {
+		s := "```go\nif a < b && **c** {\n```"
+		assert.Equal(Convert(s, Mrkdwn), "```\nif a &lt; b &amp;&amp; **c** {\n```")
+		assert.Equal(Convert(s, Plain), "if a < b && **c** {")
+
+		// ~~~ のコードブロック内の ``` は閉じない
+		s = "~~~md\n```go\n~~~"
+		assert.Equal(Convert(s, Mrkdwn), "```\n```go\n```")
+		assert.Equal(Convert(s, Markdown), "~~~\n```go\n~~~")
+	})
+
+	t.Run("html", func(t *testing.T) {
+		s := "<!-- 変更内容を記載してください -->\nline1<br>line2\n<details><summary>Log</summary>\nbody\n</details>"
+		assert.Equal(Convert(s, Mrkdwn), "line1 line2\nLog\nbody")
+	})
+
+	t.Run("template", func(t *testing.T) {
+		s := "## 概要\r\n<!-- 概要 -->\r\nFix typo\r\n\r\n## 関連 Issue\r\n<!-- #123 -->\r\n\r\n\r\n\r\n## チェックリスト\r\n- [x] テスト\r\n\r\n## 備考\r\n"
+		assert.Equal(Convert(s, Mrkdwn), "*概要*\n\nFix typo\n\n*チェックリスト*\n☑ テスト")
+	})
+}
@@ -0,0 +1,118 @@
+package message
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v38/github"
+)
+
+var (
+	coAuthorTrailer = regexp.MustCompile(`(?mi)^co-authored-by:\s*(.*?)\s*<([^>]*)>\s*$`)
+	noreplyEmail = regexp.MustCompile(`(?i)^(?:\d+\+)?([^@]+)@users\.noreply\.github\.com$`)
+)
+
+type commitAuthor struct {
+	name string
+	email string
+	login string
+}
+
+func newCommitAuthor(a *github.CommitAuthor) commitAuthor {
+	ca := commitAuthor{name: a.GetName(), email: a.GetEmail(), login: a.GetLogin()}
+	if ca.login == "" {
+		ca.login = loginFromEmail(ca.email)
+	}
+	return ca
+}
+
+// Co-authored-by: Name <email> 形式の trailer を取得する
+func parseCoAuthors(message string) []commitAuthor {
+	var authors []commitAuthor
+	for _, m := range coAuthorTrailer.FindAllStringSubmatch(message, -1) {
+		authors = append(
+			authors,
+			commitAuthor{name: m[1], email: m[2], login: loginFromEmail(m[2])},
+		)
+	}
+	return authors
+}
+
+// trailer を除いたコミットメッセージ
+func stripCoAuthors(message string) string {
+	return strings.TrimSpace(coAuthorTrailer.ReplaceAllString(message, ""))
+}
+
+func loginFromEmail(email string) string {
+	if m := noreplyEmail.FindStringSubmatch(email); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// message.mentions (login もしくは email: Slack のユーザー ID) の login もしくは email を小文字にする
+func normalizeMentions(m map[string]string) map[string]string {
+	mentions := map[string]string{}
+	for key, id := range m {
+		mentions[strings.ToLower(key)] = id
+	}
+	return mentions
+}
+
+// Slack のユーザー ID が分かる場合はメンションにする
+func (gm *GitHubMessage) mention(a commitAuthor) string {
+	for _, key := range []string{a.login, a.email, a.name} {
+		if key == "" {
+			continue
+		}
+		if id, ok := gm.activeMentions()[strings.ToLower(key)]; ok {
+			return fmt.Sprintf("<@%s>", id)
+		}
+	}
+	if a.login != "" {
+		return a.login
+	}
+	return a.name
+}
+
+func (gm *GitHubMessage) pushCommitLine(c *github.HeadCommit) string {
+	authors := []string{gm.mention(newCommitAuthor(c.GetAuthor()))}
+	for _, co := range parseCoAuthors(c.GetMessage()) {
+		authors = append(authors, gm.mention(co))
+	}
+	return fmt.Sprintf(
+		"<%s|%s> %s (%s)\n",
+		c.GetURL(), shortSHA(c.GetID()), stripCoAuthors(c.GetMessage()), strings.Join(authors, ", "),
+	)
+}
+
+// 作成者 (Co-author を含む) ごとのコミット数を多い順に並べる
+func (gm *GitHubMessage) pushAuthorSummary(commits []*github.HeadCommit) string {
+	counts := map[string]int{}
+	var names []string
+	for _, c := range commits {
+		authors := append([]commitAuthor{newCommitAuthor(c.GetAuthor())}, parseCoAuthors(c.GetMessage())...)
+		seen := map[string]bool{}
+		for _, a := range authors {
+			name := gm.mention(a)
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			if counts[name] == 0 {
+				names = append(names, name)
+			}
+			counts[name]++
+		}
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		return counts[names[i]] > counts[names[j]]
+	})
+	summary := make([]string, 0, len(names))
+	for _, name := range names {
+		summary = append(summary, fmt.Sprintf("%s (%d)", name, counts[name]))
+	}
+	return strings.Join(summary, ", ")
+}
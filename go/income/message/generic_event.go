@@ -0,0 +1,111 @@
+package message
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SongCastle/ggnb/income/builder"
+	"github.com/SongCastle/ggnb/income/i18n"
+)
+
+// 対象 (html_url を持つオブジェクト) の探索から除くキー
+var genericContextKeys = map[string]bool{
+	"enterprise":   true,
+	"installation": true,
+	"organization": true,
+	"repository":   true,
+	"sender":       true,
+}
+
+// ToPayload で扱わない Event を、共通の項目 (sender, action, repository 等) から組み立てる
+// (go-github の型・ローカルの型・map[string]interface{} のいずれも JSON を経由して扱う)
+func buildGenericEvent(l *i18n.Localizer, eventType string, event interface{}) (*bytes.Buffer, error) {
+	m, err := eventData(event)
+	if err != nil {
+		return nil, err
+	}
+
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), jsonPathString(m, "sender.login"), true)
+	if action := jsonPathString(m, "action"); action != "" {
+		a.InsertField(l.T("field.action"), fmt.Sprintf("%s (%s)", eventType, action), true)
+	} else {
+		a.InsertField(l.T("field.action"), eventType, true)
+	}
+	if repo := jsonPathString(m, "repository.full_name"); repo != "" {
+		a.InsertField(l.T("field.repository"), repo, true)
+	} else if org := jsonPathString(m, "organization.login"); org != "" {
+		a.InsertField(l.T("field.organization"), org, true)
+	}
+	key, url := genericMainObject(m)
+	if title := genericTitle(m, key); title != "" {
+		a.InsertField(l.T("field.title"), title)
+	}
+	if url == "" {
+		url = jsonPathString(m, "repository.html_url")
+	}
+	if url != "" {
+		a.InsertField(l.T("field.link"), url)
+	}
+	return a.Build()
+}
+
+// Event を JSON のキーで参照できる map にする
+func eventData(event interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// html_url を持つ最初の (キー順) オブジェクトを対象とする
+func genericMainObject(m map[string]interface{}) (string, string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if genericContextKeys[k] {
+			continue
+		}
+		if url := jsonPathString(m, k+".html_url"); url != "" {
+			return k, url
+		}
+	}
+	return "", ""
+}
+
+func genericTitle(m map[string]interface{}, key string) string {
+	if key == "" {
+		return ""
+	}
+	for _, field := range []string{"title", "name"} {
+		if title := jsonPathString(m, key+"."+field); title != "" {
+			return title
+		}
+	}
+	return ""
+}
+
+// "a.b.c" 形式のパスで文字列を取得する (存在しない場合は空文字)
+func jsonPathString(m map[string]interface{}, path string) string {
+	var v interface{} = m
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		v = obj[key]
+	}
+	s, _ := v.(string)
+	return s
+}
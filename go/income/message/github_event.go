@@ -0,0 +1,1112 @@
+package message
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/SongCastle/ggnb/income/i18n"
+	"github.com/google/go-github/v38/github"
+)
+
+// go-github (v38) が対応していない、もしくは変更内容 (changes) を持たない Event を扱う
+var localEventTypes = map[string]func() interface{}{
+	"branch_protection_rule": func() interface{} { return &branchProtectionRuleEvent{} },
+	"code_scanning_alert": func() interface{} { return &securityAlertEvent{} },
+	"dependabot_alert": func() interface{} { return &securityAlertEvent{} },
+	"deployment_status": func() interface{} { return &deploymentStatusEvent{} },
+	"issues": func() interface{} { return &issuesEvent{} },
+	"label": func() interface{} { return &labelEvent{} },
+	"marketplace_purchase": func() interface{} { return &marketplacePurchaseEvent{} },
+	"member": func() interface{} { return &memberEvent{} },
+	"merge_group": func() interface{} { return &mergeGroupEvent{} },
+	"milestone": func() interface{} { return &milestoneEvent{} },
+	"package": func() interface{} { return &packageEvent{} },
+	"ping": func() interface{} { return &pingEvent{} },
+	"projects_v2_item": func() interface{} { return &projectsV2ItemEvent{} },
+	"registry_package": func() interface{} { return &packageEvent{} },
+	"repository": func() interface{} { return &repositoryEvent{} },
+	"repository_ruleset": func() interface{} { return &repositoryRulesetEvent{} },
+	"repository_vulnerability_alert": func() interface{} { return &securityAlertEvent{} },
+	"secret_scanning_alert": func() interface{} { return &securityAlertEvent{} },
+	"security_advisory": func() interface{} { return &securityAlertEvent{} },
+	"sponsorship": func() interface{} { return &sponsorshipEvent{} },
+}
+
+func parseWebHook(eventType string, payload []byte) (interface{}, error) {
+	newEvent, ok := localEventTypes[eventType]
+	if !ok {
+		return github.ParseWebHook(eventType, payload)
+	}
+	event := newEvent()
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type changeFrom struct {
+	From *string `json:"from,omitempty"`
+}
+
+func (c *changeFrom) GetFrom() string {
+	if c == nil || c.From == nil {
+		return ""
+	}
+	return *c.From
+}
+
+type memberEvent struct {
+	github.MemberEvent
+	Changes *memberChanges `json:"changes,omitempty"`
+}
+
+type memberChanges struct {
+	Permission *struct {
+		From *string `json:"from,omitempty"`
+		To *string `json:"to,omitempty"`
+	} `json:"permission,omitempty"`
+	OldPermission *changeFrom `json:"old_permission,omitempty"`
+}
+
+func (e *memberEvent) GetPermissionFrom() string {
+	if e.Changes == nil {
+		return ""
+	}
+	if p := e.Changes.Permission; p != nil && p.From != nil {
+		return *p.From
+	}
+	return e.Changes.OldPermission.GetFrom()
+}
+
+func (e *memberEvent) GetPermissionTo() string {
+	if e.Changes == nil || e.Changes.Permission == nil || e.Changes.Permission.To == nil {
+		return ""
+	}
+	return *e.Changes.Permission.To
+}
+
+type repositoryEvent struct {
+	github.RepositoryEvent
+	Changes *repositoryChanges `json:"changes,omitempty"`
+}
+
+type repositoryChanges struct {
+	Repository *struct {
+		Name *changeFrom `json:"name,omitempty"`
+	} `json:"repository,omitempty"`
+	Owner *struct {
+		From *struct {
+			User *github.User `json:"user,omitempty"`
+			Organization *github.Organization `json:"organization,omitempty"`
+		} `json:"from,omitempty"`
+	} `json:"owner,omitempty"`
+}
+
+func (e *repositoryEvent) GetNameFrom() string {
+	if e.Changes == nil || e.Changes.Repository == nil {
+		return ""
+	}
+	return e.Changes.Repository.Name.GetFrom()
+}
+
+func (e *repositoryEvent) GetOwnerFrom() string {
+	if e.Changes == nil || e.Changes.Owner == nil || e.Changes.Owner.From == nil {
+		return ""
+	}
+	from := e.Changes.Owner.From
+	if from.Organization != nil {
+		return from.Organization.GetLogin()
+	}
+	return from.User.GetLogin()
+}
+
+type branchProtectionRuleEvent struct {
+	Action *string `json:"action,omitempty"`
+	Rule *branchProtectionRule `json:"rule,omitempty"`
+	Changes map[string]*rawChangeFrom `json:"changes,omitempty"`
+	Repo *github.Repository `json:"repository,omitempty"`
+	Org *github.Organization `json:"organization,omitempty"`
+	Sender *github.User `json:"sender,omitempty"`
+}
+
+// 変更前の値が文字列とは限らない changes (数値, 真偽値, 配列など)
+type rawChangeFrom struct {
+	From json.RawMessage `json:"from,omitempty"`
+}
+
+func (c *rawChangeFrom) GetFrom() string {
+	if c == nil || len(c.From) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(c.From, &s); err == nil {
+		return s
+	}
+	return string(c.From)
+}
+
+type branchProtectionRule struct {
+	ID *int64 `json:"id,omitempty"`
+	Name *string `json:"name,omitempty"`
+	AdminEnforced *bool `json:"admin_enforced,omitempty"`
+	RequiredApprovingReviewCount *int `json:"required_approving_review_count,omitempty"`
+	RequireCodeOwnerReview *bool `json:"require_code_owner_review,omitempty"`
+	RequiredStatusChecks []string `json:"required_status_checks,omitempty"`
+	AllowForcePushesEnforcementLevel *string `json:"allow_force_pushes_enforcement_level,omitempty"`
+	AllowDeletionsEnforcementLevel *string `json:"allow_deletions_enforcement_level,omitempty"`
+}
+
+func (e *branchProtectionRuleEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *branchProtectionRuleEvent) GetRule() *branchProtectionRule {
+	if e == nil {
+		return nil
+	}
+	return e.Rule
+}
+
+func (e *branchProtectionRuleEvent) GetRepo() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repo
+}
+
+func (e *branchProtectionRuleEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+func (r *branchProtectionRule) GetName() string {
+	if r == nil || r.Name == nil {
+		return ""
+	}
+	return *r.Name
+}
+
+func (r *branchProtectionRule) GetAdminEnforced() bool {
+	if r == nil || r.AdminEnforced == nil {
+		return false
+	}
+	return *r.AdminEnforced
+}
+
+func (r *branchProtectionRule) GetRequiredApprovingReviewCount() int {
+	if r == nil || r.RequiredApprovingReviewCount == nil {
+		return 0
+	}
+	return *r.RequiredApprovingReviewCount
+}
+
+func (r *branchProtectionRule) GetRequireCodeOwnerReview() bool {
+	if r == nil || r.RequireCodeOwnerReview == nil {
+		return false
+	}
+	return *r.RequireCodeOwnerReview
+}
+
+func (r *branchProtectionRule) GetAllowForcePushesEnforcementLevel() string {
+	if r == nil || r.AllowForcePushesEnforcementLevel == nil {
+		return ""
+	}
+	return *r.AllowForcePushesEnforcementLevel
+}
+
+func (r *branchProtectionRule) GetAllowDeletionsEnforcementLevel() string {
+	if r == nil || r.AllowDeletionsEnforcementLevel == nil {
+		return ""
+	}
+	return *r.AllowDeletionsEnforcementLevel
+}
+
+type repositoryRulesetEvent struct {
+	Action *string `json:"action,omitempty"`
+	Ruleset *repositoryRuleset `json:"repository_ruleset,omitempty"`
+	Repo *github.Repository `json:"repository,omitempty"`
+	Org *github.Organization `json:"organization,omitempty"`
+	Sender *github.User `json:"sender,omitempty"`
+}
+
+type repositoryRuleset struct {
+	ID *int64 `json:"id,omitempty"`
+	Name *string `json:"name,omitempty"`
+	Target *string `json:"target,omitempty"`
+	SourceType *string `json:"source_type,omitempty"`
+	Source *string `json:"source,omitempty"`
+	Enforcement *string `json:"enforcement,omitempty"`
+	Rules []*struct {
+		Type *string `json:"type,omitempty"`
+	} `json:"rules,omitempty"`
+}
+
+func (e *repositoryRulesetEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *repositoryRulesetEvent) GetRuleset() *repositoryRuleset {
+	if e == nil {
+		return nil
+	}
+	return e.Ruleset
+}
+
+func (e *repositoryRulesetEvent) GetRepo() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repo
+}
+
+func (e *repositoryRulesetEvent) GetOrg() *github.Organization {
+	if e == nil {
+		return nil
+	}
+	return e.Org
+}
+
+func (e *repositoryRulesetEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+func (r *repositoryRuleset) GetID() int64 {
+	if r == nil || r.ID == nil {
+		return 0
+	}
+	return *r.ID
+}
+
+func (r *repositoryRuleset) GetName() string {
+	if r == nil || r.Name == nil {
+		return ""
+	}
+	return *r.Name
+}
+
+func (r *repositoryRuleset) GetTarget() string {
+	if r == nil || r.Target == nil {
+		return ""
+	}
+	return *r.Target
+}
+
+func (r *repositoryRuleset) GetEnforcement() string {
+	if r == nil || r.Enforcement == nil {
+		return ""
+	}
+	return *r.Enforcement
+}
+
+func (r *repositoryRuleset) GetRuleTypes() []string {
+	if r == nil {
+		return nil
+	}
+	types := make([]string, 0, len(r.Rules))
+	for _, rule := range r.Rules {
+		if rule != nil && rule.Type != nil {
+			types = append(types, *rule.Type)
+		}
+	}
+	return types
+}
+
+type labelEvent struct {
+	github.LabelEvent
+	Changes *labelChanges `json:"changes,omitempty"`
+	Sender *github.User `json:"sender,omitempty"`
+}
+
+type labelChanges struct {
+	Name *changeFrom `json:"name,omitempty"`
+	Color *changeFrom `json:"color,omitempty"`
+	Description *changeFrom `json:"description,omitempty"`
+}
+
+func (e *labelEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+func (e *labelEvent) GetChanges() *labelChanges {
+	if e == nil {
+		return nil
+	}
+	return e.Changes
+}
+
+func (c *labelChanges) GetName() *changeFrom {
+	if c == nil {
+		return nil
+	}
+	return c.Name
+}
+
+func (c *labelChanges) GetColor() *changeFrom {
+	if c == nil {
+		return nil
+	}
+	return c.Color
+}
+
+func (c *labelChanges) GetDescription() *changeFrom {
+	if c == nil {
+		return nil
+	}
+	return c.Description
+}
+
+type milestoneEvent struct {
+	github.MilestoneEvent
+	Changes *milestoneChanges `json:"changes,omitempty"`
+}
+
+type milestoneChanges struct {
+	Title *changeFrom `json:"title,omitempty"`
+	Description *changeFrom `json:"description,omitempty"`
+	DueOn *changeFrom `json:"due_on,omitempty"`
+}
+
+func (e *milestoneEvent) GetChanges() *milestoneChanges {
+	if e == nil {
+		return nil
+	}
+	return e.Changes
+}
+
+func (c *milestoneChanges) GetTitle() *changeFrom {
+	if c == nil {
+		return nil
+	}
+	return c.Title
+}
+
+func (c *milestoneChanges) GetDescription() *changeFrom {
+	if c == nil {
+		return nil
+	}
+	return c.Description
+}
+
+func (c *milestoneChanges) GetDueOn() *changeFrom {
+	if c == nil {
+		return nil
+	}
+	return c.DueOn
+}
+
+type pingEvent struct {
+	github.PingEvent
+	Repo *github.Repository `json:"repository,omitempty"`
+	Org *github.Organization `json:"organization,omitempty"`
+	Sender *github.User `json:"sender,omitempty"`
+}
+
+func (e *pingEvent) GetRepo() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repo
+}
+
+func (e *pingEvent) GetOrg() *github.Organization {
+	if e == nil {
+		return nil
+	}
+	return e.Org
+}
+
+func (e *pingEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+type projectsV2ItemEvent struct {
+	Action *string `json:"action,omitempty"`
+	Item *projectsV2Item `json:"projects_v2_item,omitempty"`
+	Changes *projectsV2ItemChanges `json:"changes,omitempty"`
+	Org *github.Organization `json:"organization,omitempty"`
+	Sender *github.User `json:"sender,omitempty"`
+}
+
+type projectsV2Item struct {
+	ID *int64 `json:"id,omitempty"`
+	NodeID *string `json:"node_id,omitempty"`
+	ProjectNodeID *string `json:"project_node_id,omitempty"`
+	ContentNodeID *string `json:"content_node_id,omitempty"`
+	ContentType *string `json:"content_type,omitempty"`
+}
+
+type projectsV2ItemChanges struct {
+	FieldValue *struct {
+		FieldNodeID *string `json:"field_node_id,omitempty"`
+		FieldType *string `json:"field_type,omitempty"`
+		FieldName *string `json:"field_name,omitempty"`
+		ProjectNumber *int `json:"project_number,omitempty"`
+		From json.RawMessage `json:"from,omitempty"`
+		To json.RawMessage `json:"to,omitempty"`
+	} `json:"field_value,omitempty"`
+	ArchivedAt *rawChangeFrom `json:"archived_at,omitempty"`
+}
+
+func (e *projectsV2ItemEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *projectsV2ItemEvent) GetItem() *projectsV2Item {
+	if e == nil {
+		return nil
+	}
+	return e.Item
+}
+
+func (e *projectsV2ItemEvent) GetOrg() *github.Organization {
+	if e == nil {
+		return nil
+	}
+	return e.Org
+}
+
+func (e *projectsV2ItemEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+func (e *projectsV2ItemEvent) GetFieldName() string {
+	if e == nil || e.Changes == nil || e.Changes.FieldValue == nil {
+		return ""
+	}
+	if name := e.Changes.FieldValue.FieldName; name != nil {
+		return *name
+	}
+	if t := e.Changes.FieldValue.FieldType; t != nil {
+		return *t
+	}
+	return ""
+}
+
+func (e *projectsV2ItemEvent) GetFieldValueFrom(l *i18n.Localizer) string {
+	if e == nil || e.Changes == nil || e.Changes.FieldValue == nil {
+		return ""
+	}
+	return projectFieldValue(l, e.Changes.FieldValue.From)
+}
+
+func (e *projectsV2ItemEvent) GetFieldValueTo(l *i18n.Localizer) string {
+	if e == nil || e.Changes == nil || e.Changes.FieldValue == nil {
+		return ""
+	}
+	return projectFieldValue(l, e.Changes.FieldValue.To)
+}
+
+func (e *projectsV2ItemEvent) GetProjectNumber() int {
+	if e == nil || e.Changes == nil || e.Changes.FieldValue == nil || e.Changes.FieldValue.ProjectNumber == nil {
+		return 0
+	}
+	return *e.Changes.FieldValue.ProjectNumber
+}
+
+func (i *projectsV2Item) GetContentType() string {
+	if i == nil || i.ContentType == nil {
+		return ""
+	}
+	return *i.ContentType
+}
+
+func (i *projectsV2Item) GetContentNodeID() string {
+	if i == nil || i.ContentNodeID == nil {
+		return ""
+	}
+	return *i.ContentNodeID
+}
+
+// フィールドの値は種類によって文字列, 数値, オブジェクト (Single select, Iteration) となる
+func projectFieldValue(l *i18n.Localizer, raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return l.T("value.empty")
+	}
+	var option struct {
+		Name *string `json:"name,omitempty"`
+		Title *string `json:"title,omitempty"`
+	}
+	if err := json.Unmarshal(raw, &option); err == nil {
+		if option.Name != nil {
+			return *option.Name
+		}
+		if option.Title != nil {
+			return *option.Title
+		}
+	}
+	return (&rawChangeFrom{From: raw}).GetFrom()
+}
+
+// package と registry_package は payload のキーのみ異なる
+type packageEvent struct {
+	Action *string `json:"action,omitempty"`
+	Package *githubPackage `json:"package,omitempty"`
+	RegistryPackage *githubPackage `json:"registry_package,omitempty"`
+	Repo *github.Repository `json:"repository,omitempty"`
+	Org *github.Organization `json:"organization,omitempty"`
+	Sender *github.User `json:"sender,omitempty"`
+}
+
+func (e *packageEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+// github.Package のメソッドを呼べるよう、存在しない場合も nil は返さない
+func (e *packageEvent) GetPackage() *githubPackage {
+	if e != nil && e.Package != nil {
+		return e.Package
+	}
+	if e != nil && e.RegistryPackage != nil {
+		return e.RegistryPackage
+	}
+	return &githubPackage{}
+}
+
+func (e *packageEvent) GetRepo() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repo
+}
+
+func (e *packageEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// go-github (v38) の Package は ecosystem とコンテナのメタデータを持たない
+type githubPackage struct {
+	github.Package
+	Ecosystem *string `json:"ecosystem,omitempty"`
+	PackageVersion *packageVersion `json:"package_version,omitempty"`
+}
+
+func (p *githubPackage) GetEcosystem() string {
+	if p == nil {
+		return ""
+	}
+	if p.Ecosystem != nil {
+		return *p.Ecosystem
+	}
+	return p.GetPackageType()
+}
+
+func (p *githubPackage) GetPackageVersion() *packageVersion {
+	if p == nil || p.PackageVersion == nil {
+		return &packageVersion{}
+	}
+	return p.PackageVersion
+}
+
+type packageVersion struct {
+	github.PackageVersion
+	ContainerMetadata *struct {
+		Tag *struct {
+			Name *string `json:"name,omitempty"`
+			Digest *string `json:"digest,omitempty"`
+		} `json:"tag,omitempty"`
+	} `json:"container_metadata,omitempty"`
+}
+
+// コンテナの場合はタグ名、それ以外はバージョン
+func (v *packageVersion) GetTag() string {
+	if v == nil {
+		return ""
+	}
+	if m := v.ContainerMetadata; m != nil && m.Tag != nil && m.Tag.Name != nil && *m.Tag.Name != "" {
+		return *m.Tag.Name
+	}
+	if strings.HasPrefix(v.GetVersion(), "sha256:") {
+		return ""
+	}
+	return v.GetVersion()
+}
+
+func (v *packageVersion) GetDigest() string {
+	if v == nil {
+		return ""
+	}
+	if m := v.ContainerMetadata; m != nil && m.Tag != nil && m.Tag.Digest != nil && *m.Tag.Digest != "" {
+		return *m.Tag.Digest
+	}
+	if strings.HasPrefix(v.GetVersion(), "sha256:") {
+		return v.GetVersion()
+	}
+	return ""
+}
+
+type mergeGroupEvent struct {
+	Action *string `json:"action,omitempty"`
+	// destroyed の場合のみ (merged, invalidated, dequeued)
+	Reason *string `json:"reason,omitempty"`
+	MergeGroup *mergeGroup `json:"merge_group,omitempty"`
+	Repo *github.Repository `json:"repository,omitempty"`
+	Org *github.Organization `json:"organization,omitempty"`
+	Sender *github.User `json:"sender,omitempty"`
+}
+
+type mergeGroup struct {
+	HeadSHA *string `json:"head_sha,omitempty"`
+	HeadRef *string `json:"head_ref,omitempty"`
+	BaseSHA *string `json:"base_sha,omitempty"`
+	BaseRef *string `json:"base_ref,omitempty"`
+	HeadCommit *github.HeadCommit `json:"head_commit,omitempty"`
+}
+
+func (e *mergeGroupEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *mergeGroupEvent) GetReason() string {
+	if e == nil || e.Reason == nil {
+		return ""
+	}
+	return *e.Reason
+}
+
+func (e *mergeGroupEvent) GetMergeGroup() *mergeGroup {
+	if e == nil {
+		return nil
+	}
+	return e.MergeGroup
+}
+
+func (e *mergeGroupEvent) GetRepo() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repo
+}
+
+func (e *mergeGroupEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+func (g *mergeGroup) GetHeadSHA() string {
+	if g == nil || g.HeadSHA == nil {
+		return ""
+	}
+	return *g.HeadSHA
+}
+
+func (g *mergeGroup) GetHeadRef() string {
+	if g == nil || g.HeadRef == nil {
+		return ""
+	}
+	return *g.HeadRef
+}
+
+func (g *mergeGroup) GetBaseRef() string {
+	if g == nil || g.BaseRef == nil {
+		return ""
+	}
+	return *g.BaseRef
+}
+
+func (g *mergeGroup) GetHeadCommit() *github.HeadCommit {
+	if g == nil {
+		return nil
+	}
+	return g.HeadCommit
+}
+
+// go-github (v38) の MarketplacePurchase は購入したアカウントを持たない
+type marketplacePurchaseEvent struct {
+	github.MarketplacePurchaseEvent
+	MarketplacePurchase *marketplacePurchase `json:"marketplace_purchase,omitempty"`
+	PreviousMarketplacePurchase *marketplacePurchase `json:"previous_marketplace_purchase,omitempty"`
+}
+
+type marketplacePurchase struct {
+	github.MarketplacePurchase
+	Account *github.MarketplacePlanAccount `json:"account,omitempty"`
+}
+
+// github.MarketplacePurchase のメソッドを呼べるよう、存在しない場合も nil は返さない
+func (e *marketplacePurchaseEvent) GetMarketplacePurchase() *marketplacePurchase {
+	if e == nil || e.MarketplacePurchase == nil {
+		return &marketplacePurchase{}
+	}
+	return e.MarketplacePurchase
+}
+
+// 存在しない場合は nil
+func (e *marketplacePurchaseEvent) GetPreviousMarketplacePurchase() *marketplacePurchase {
+	if e == nil {
+		return nil
+	}
+	return e.PreviousMarketplacePurchase
+}
+
+func (p *marketplacePurchase) GetAccount() *github.MarketplacePlanAccount {
+	if p == nil {
+		return nil
+	}
+	return p.Account
+}
+
+type sponsorshipEvent struct {
+	Action *string `json:"action,omitempty"`
+	EffectiveDate *string `json:"effective_date,omitempty"`
+	Sponsorship *sponsorship `json:"sponsorship,omitempty"`
+	Changes *sponsorshipChanges `json:"changes,omitempty"`
+	Org *github.Organization `json:"organization,omitempty"`
+	Sender *github.User `json:"sender,omitempty"`
+}
+
+type sponsorship struct {
+	Sponsorable *github.User `json:"sponsorable,omitempty"`
+	Sponsor *github.User `json:"sponsor,omitempty"`
+	PrivacyLevel *string `json:"privacy_level,omitempty"`
+	Tier *sponsorshipTier `json:"tier,omitempty"`
+}
+
+type sponsorshipTier struct {
+	Name *string `json:"name,omitempty"`
+	MonthlyPriceInCents *int `json:"monthly_price_in_cents,omitempty"`
+	MonthlyPriceInDollars *int `json:"monthly_price_in_dollars,omitempty"`
+	IsOneTime *bool `json:"is_one_time,omitempty"`
+}
+
+type sponsorshipChanges struct {
+	Tier *struct {
+		From *sponsorshipTier `json:"from,omitempty"`
+	} `json:"tier,omitempty"`
+	PrivacyLevel *changeFrom `json:"privacy_level,omitempty"`
+}
+
+func (e *sponsorshipEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *sponsorshipEvent) GetEffectiveDate() string {
+	if e == nil || e.EffectiveDate == nil {
+		return ""
+	}
+	return *e.EffectiveDate
+}
+
+func (e *sponsorshipEvent) GetSponsorship() *sponsorship {
+	if e == nil {
+		return nil
+	}
+	return e.Sponsorship
+}
+
+func (e *sponsorshipEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+func (e *sponsorshipEvent) GetTierFrom() *sponsorshipTier {
+	if e == nil || e.Changes == nil || e.Changes.Tier == nil {
+		return nil
+	}
+	return e.Changes.Tier.From
+}
+
+func (e *sponsorshipEvent) GetPrivacyLevelFrom() string {
+	if e == nil || e.Changes == nil {
+		return ""
+	}
+	return e.Changes.PrivacyLevel.GetFrom()
+}
+
+func (s *sponsorship) GetSponsorable() *github.User {
+	if s == nil {
+		return nil
+	}
+	return s.Sponsorable
+}
+
+func (s *sponsorship) GetSponsor() *github.User {
+	if s == nil {
+		return nil
+	}
+	return s.Sponsor
+}
+
+func (s *sponsorship) GetPrivacyLevel() string {
+	if s == nil || s.PrivacyLevel == nil {
+		return ""
+	}
+	return *s.PrivacyLevel
+}
+
+func (s *sponsorship) GetTier() *sponsorshipTier {
+	if s == nil {
+		return nil
+	}
+	return s.Tier
+}
+
+func (t *sponsorshipTier) GetName() string {
+	if t == nil || t.Name == nil {
+		return ""
+	}
+	return *t.Name
+}
+
+// monthly_price_in_cents が無い場合は monthly_price_in_dollars から求める
+func (t *sponsorshipTier) GetMonthlyPriceInCents() int {
+	if t == nil {
+		return 0
+	}
+	if t.MonthlyPriceInCents != nil {
+		return *t.MonthlyPriceInCents
+	}
+	if t.MonthlyPriceInDollars != nil {
+		return *t.MonthlyPriceInDollars * 100
+	}
+	return 0
+}
+
+func (t *sponsorshipTier) GetIsOneTime() bool {
+	if t == nil || t.IsOneTime == nil {
+		return false
+	}
+	return *t.IsOneTime
+}
+
+// go-github (v38) の IssuesEvent は譲渡先 (new_issue, new_repository) を持たない
+type issuesEvent struct {
+	github.IssuesEvent
+	Changes *issuesChanges `json:"changes,omitempty"`
+}
+
+type issuesChanges struct {
+	github.EditChange
+	NewIssue *github.Issue `json:"new_issue,omitempty"`
+	NewRepository *github.Repository `json:"new_repository,omitempty"`
+}
+
+func (e *issuesEvent) GetChanges() *github.EditChange {
+	if e == nil || e.Changes == nil {
+		return nil
+	}
+	return &e.Changes.EditChange
+}
+
+func (e *issuesEvent) GetNewIssue() *github.Issue {
+	if e == nil || e.Changes == nil {
+		return nil
+	}
+	return e.Changes.NewIssue
+}
+
+func (e *issuesEvent) GetNewRepository() *github.Repository {
+	if e == nil || e.Changes == nil {
+		return nil
+	}
+	return e.Changes.NewRepository
+}
+
+// action を持たないため
+type deploymentStatusEvent struct {
+	github.DeploymentStatusEvent
+	Action *string `json:"action,omitempty"`
+}
+
+func (e *deploymentStatusEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+// code_scanning_alert, dependabot_alert, secret_scanning_alert, repository_vulnerability_alert, security_advisory
+// Event ごとに含まれる項目が異なるため、いずれかの項目から概要・重要度・パッケージ・リンクを取り出す
+type securityAlertEvent struct {
+	Action *string `json:"action,omitempty"`
+	Ref *string `json:"ref,omitempty"`
+	Alert *securityAlert `json:"alert,omitempty"`
+	SecurityAdvisory *securityAdvisory `json:"security_advisory,omitempty"`
+	Repo *github.Repository `json:"repository,omitempty"`
+	Org *github.Organization `json:"organization,omitempty"`
+	Sender *github.User `json:"sender,omitempty"`
+}
+
+type securityAlert struct {
+	Number *int `json:"number,omitempty"`
+	HTMLURL *string `json:"html_url,omitempty"`
+	State *string `json:"state,omitempty"`
+	// code_scanning_alert
+	Rule *struct {
+		Description *string `json:"description,omitempty"`
+		Severity *string `json:"severity,omitempty"`
+		SecuritySeverityLevel *string `json:"security_severity_level,omitempty"`
+	} `json:"rule,omitempty"`
+	// secret_scanning_alert
+	SecretTypeDisplayName *string `json:"secret_type_display_name,omitempty"`
+	SecretType *string `json:"secret_type,omitempty"`
+	// dependabot_alert
+	SecurityAdvisory *securityAdvisory `json:"security_advisory,omitempty"`
+	Dependency *struct {
+		Package *struct {
+			Ecosystem *string `json:"ecosystem,omitempty"`
+			Name *string `json:"name,omitempty"`
+		} `json:"package,omitempty"`
+	} `json:"dependency,omitempty"`
+	// repository_vulnerability_alert
+	AffectedPackageName *string `json:"affected_package_name,omitempty"`
+	AffectedRange *string `json:"affected_range,omitempty"`
+	ExternalIdentifier *string `json:"external_identifier,omitempty"`
+	Severity *string `json:"severity,omitempty"`
+}
+
+type securityAdvisory struct {
+	GHSAID *string `json:"ghsa_id,omitempty"`
+	Summary *string `json:"summary,omitempty"`
+	Severity *string `json:"severity,omitempty"`
+	HTMLURL *string `json:"html_url,omitempty"`
+}
+
+func (e *securityAlertEvent) GetAction() string {
+	if e == nil || e.Action == nil {
+		return ""
+	}
+	return *e.Action
+}
+
+func (e *securityAlertEvent) GetRepo() *github.Repository {
+	if e == nil {
+		return nil
+	}
+	return e.Repo
+}
+
+func (e *securityAlertEvent) GetSender() *github.User {
+	if e == nil {
+		return nil
+	}
+	return e.Sender
+}
+
+// 空でない最初の値
+func firstString(values ...*string) string {
+	for _, v := range values {
+		if v != nil && *v != "" {
+			return *v
+		}
+	}
+	return ""
+}
+
+func (e *securityAlertEvent) advisory() *securityAdvisory {
+	if e.Alert != nil && e.Alert.SecurityAdvisory != nil {
+		return e.Alert.SecurityAdvisory
+	}
+	if e.SecurityAdvisory != nil {
+		return e.SecurityAdvisory
+	}
+	return &securityAdvisory{}
+}
+
+func (e *securityAlertEvent) GetSummary() string {
+	if e == nil {
+		return ""
+	}
+	a := e.Alert
+	if a == nil {
+		a = &securityAlert{}
+	}
+	var rule *string
+	if a.Rule != nil {
+		rule = a.Rule.Description
+	}
+	adv := e.advisory()
+	return firstString(rule, a.SecretTypeDisplayName, a.SecretType, adv.Summary, a.ExternalIdentifier, adv.GHSAID)
+}
+
+func (e *securityAlertEvent) GetSeverity() string {
+	if e == nil {
+		return ""
+	}
+	a := e.Alert
+	if a == nil {
+		a = &securityAlert{}
+	}
+	var level, severity *string
+	if a.Rule != nil {
+		level, severity = a.Rule.SecuritySeverityLevel, a.Rule.Severity
+	}
+	return firstString(level, severity, e.advisory().Severity, a.Severity)
+}
+
+// <ecosystem>/<name> もしくは <name> (<affected_range>)
+func (e *securityAlertEvent) GetPackage() string {
+	if e == nil || e.Alert == nil {
+		return ""
+	}
+	a := e.Alert
+	if d := a.Dependency; d != nil && d.Package != nil {
+		if eco := firstString(d.Package.Ecosystem); eco != "" {
+			return eco + "/" + firstString(d.Package.Name)
+		}
+		return firstString(d.Package.Name)
+	}
+	if name := firstString(a.AffectedPackageName); name != "" {
+		if r := firstString(a.AffectedRange); r != "" {
+			return name + " (" + r + ")"
+		}
+		return name
+	}
+	return ""
+}
+
+// アラートのリンクがない場合はレポジトリのセキュリティのページ
+func (e *securityAlertEvent) GetHTMLURL() string {
+	if e == nil {
+		return ""
+	}
+	var alert *string
+	if e.Alert != nil {
+		alert = e.Alert.HTMLURL
+	}
+	if url := firstString(alert, e.advisory().HTMLURL); url != "" {
+		return url
+	}
+	if repo := e.GetRepo().GetHTMLURL(); repo != "" {
+		return repo + "/security"
+	}
+	return ""
+}
@@ -2,34 +2,209 @@ package message
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/SongCastle/ggnb/config"
+	"github.com/SongCastle/ggnb/income/api"
 	"github.com/SongCastle/ggnb/income/builder"
+	"github.com/SongCastle/ggnb/income/filter"
+	"github.com/SongCastle/ggnb/income/i18n"
+	"github.com/SongCastle/ggnb/income/markdown"
+	"github.com/SongCastle/ggnb/income/template"
+	"github.com/SongCastle/ggnb/store"
 	"github.com/google/go-github/v38/github"
 )
 
 const (
 	EventHeader = "x-github-event"
 	EventHeaderCap = "X-GitHub-Event"
+	DefaultPushCommitLimit = 10
+	// Draft PR の通知方法
+	DraftMute = config.DraftMute
+	DraftSuppress = config.DraftSuppress
+	DraftShow = config.DraftShow
+	// スターを集計する日付の形式
+	starBatchDayLayout = "2006-01-02"
+	// star・watch の両方が届いた場合に同じスターとして扱う間隔
+	starPairWindow = 5 * time.Minute
 )
 
 type commitCommentEvent = github.CommitCommentEvent
 type createEvent = github.CreateEvent
 type deleteEvent = github.DeleteEvent
+type forkEvent = github.ForkEvent
+type gollumEvent = github.GollumEvent
 type issueCommentEvent = github.IssueCommentEvent
-type issuesEvent = github.IssuesEvent
+type membershipEvent = github.MembershipEvent
+type pageBuildEvent = github.PageBuildEvent
 type pullRequestEvent = github.PullRequestEvent
 type pullRequestReviewEvent = github.PullRequestReviewEvent
 type pullRequestReviewCommentEvent = github.PullRequestReviewCommentEvent
 type pullRequestTargetEvent = github.PullRequestTargetEvent
 type pushEvent = github.PushEvent
+type starEvent = github.StarEvent
+type teamAddEvent = github.TeamAddEvent
+type teamEvent = github.TeamEvent
+type watchEvent = github.WatchEvent
 
 type fields = map[string]interface{}
 
+// マージキューのブランチ (gh-readonly-queue/<base>/pr-<番号>-<SHA>)
+var mergeQueueRef = regexp.MustCompile(`/pr-(\d+)-[0-9a-f]+$`)
+
+// builder.NewAttachment で生成される attachment
+type editableAttachment interface {
+	InsertField(title, value string, short ...bool)
+	SetColor(color string)
+}
+
+// 通知対象の Event (ToPayload で扱う Event と一致させる)
+var renderedEvents = []string{
+	"branch_protection_rule",
+	"code_scanning_alert",
+	"commit_comment",
+	"create",
+	"delete",
+	"dependabot_alert",
+	"deployment_status",
+	"fork",
+	"gollum",
+	"issue_comment",
+	"issues",
+	"label",
+	"marketplace_purchase",
+	"member",
+	"membership",
+	"merge_group",
+	"milestone",
+	"package",
+	"page_build",
+	"ping",
+	"projects_v2_item",
+	"pull_request",
+	"pull_request_review",
+	"pull_request_review_comment",
+	"pull_request_target",
+	"push",
+	"registry_package",
+	"repository",
+	"repository_ruleset",
+	"repository_vulnerability_alert",
+	"secret_scanning_alert",
+	"security_advisory",
+	"sponsorship",
+	"star",
+	"team",
+	"team_add",
+	"watch",
+}
+
 type GitHubMessage struct {
+	eventType string
 	event interface{}
+	// ToPayload で扱わない Event も汎用の形式で通知する
+	renderUnhandled bool
+	routes config.Routes
+	// github.token が設定されていない場合は nil
+	api api.AbstractClient
+	store store.AbstractStore
+	// 1 より大きい場合、スターを N 件ごとにまとめて通知する
+	starBatchSize int
+	starMilestones []int
+	// 未設定の場合は DraftMute として扱う
+	draftMode string
+	// 0 の場合は DefaultPushCommitLimit
+	pushCommitLimit int
+	// GitHub の login もしくは email (小文字) から Slack のユーザー ID
+	mentions map[string]string
+	// 通知するパッケージ名のパターン (空の場合はすべて通知する)
+	packageFilter []string
+	// message.template_dir が設定されていない場合は nil
+	templates template.AbstractTemplates
+	// 本文やコメントの変換先 (未設定の場合は markdown.Mrkdwn)
+	markdownFormat string
+	// filter.rules が設定されていない場合は nil
+	filter filter.AbstractFilter
+	filterRules []string
+	filterDryRun bool
+	// WebHook URL が設定されている route
+	slackRoutes map[string]bool
+	// レポジトリの設定ファイル (repo.path が設定されていない場合は読み込まない)
+	repoConfig config.Repo
+	// Event のレポジトリの設定ファイルで上書きした設定 (ない場合は nil)
+	repo *repoSettings
+	// 通知を控える時間帯でも通知する Event
+	urgentRules []*filter.Rule
+	// filter.rules や builder (Draft PR・パッケージ等) で除外した Event (ダイジェストにも記録しない)
+	filtered bool
+}
+
+// cfg は config.Load で検証済みのもの (Markdown の形式とフィルタのルールはここで検証する)
+func newGitHubMessage(cfg *config.Config) (*GitHubMessage, error) {
+	m := cfg.Message
+	if err := markdown.Validate(m.MarkdownFormat); err != nil {
+		return nil, config.Invalid("message.markdown_format", config.MarkdownFormatEnv, fmt.Sprintf("%q is not supported", m.MarkdownFormat))
+	}
+	for _, rule := range cfg.Filter.Rules {
+		if _, err := filter.ParseRule(rule); err != nil {
+			return nil, config.Invalid("filter.rules", config.FilterRulesEnv, fmt.Sprintf("%q is not a valid rule", rule))
+		}
+	}
+	gm := &GitHubMessage{
+		renderUnhandled: m.RenderUnhandledEvents,
+		routes: cfg.Routes,
+		starBatchSize: m.StarBatchSize,
+		starMilestones: m.StarMilestones,
+		draftMode: m.DraftPullRequest,
+		pushCommitLimit: m.PushCommitLimit,
+		mentions: normalizeMentions(m.Mentions),
+		packageFilter: m.PackageFilter,
+		markdownFormat: m.MarkdownFormat,
+		filterRules: cfg.Filter.Rules,
+		filterDryRun: cfg.Filter.DryRun,
+		slackRoutes: map[string]bool{},
+		repoConfig: cfg.Repo,
+	}
+	for route := range cfg.Slack.Routes {
+		gm.slackRoutes[route] = true
+	}
+	s, err := store.NewStore(cfg.Store.Dir)
+	if err != nil {
+		return nil, err
+	}
+	gm.store = s
+	c, err := api.NewClient(cfg.GitHub.Token, cfg.GitHub.ApiUrl)
+	if err != nil {
+		return nil, err
+	}
+	gm.api = c
+	t, err := template.NewTemplates(m.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+	gm.templates = t
+	f, err := filter.NewFilter(cfg.Filter.Rules, cfg.Filter.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	gm.filter = f
+	// 通知を控える時間帯でも通知する Event は include ルールのみ
+	for _, text := range cfg.QuietHours.Urgent {
+		r, err := filter.ParseRule(text)
+		if err != nil || r.Exclude {
+			return nil, config.Invalid("quiet_hours.urgent", config.QuietHoursUrgentEnv, fmt.Sprintf("%q is not a valid include rule", text))
+		}
+		gm.urgentRules = append(gm.urgentRules, r)
+	}
+	return gm, nil
 }
 
 func (gm *GitHubMessage) Init(headers, body interface{}) error {
@@ -52,11 +227,19 @@ func (gm *GitHubMessage) setGitHubEvent(headers map[string]string, body *string)
 	if err != nil {
 		return err
 	}
-	event, err := github.ParseWebHook(eventType, []byte(*body))
+	event, err := parseWebHook(eventType, []byte(*body))
 	if err != nil {
-		return err
+		// go-github が対応していない Event は汎用の形式で扱う
+		var m map[string]interface{}
+		if !gm.renderUnhandled || json.Unmarshal([]byte(*body), &m) != nil {
+			return err
+		}
+		event = m
 	}
+	gm.eventType = eventType
 	gm.event = event
+	gm.repo = nil
+	gm.filtered = false
 	return nil
 }
 
@@ -72,383 +255,1849 @@ func extractGitHubEvent(headers map[string]string) (string, error) {
 }
 
 func (gm *GitHubMessage) ToPayload() (*bytes.Buffer, error) {
+	gm.loadRepoSettings()
+	if allowed, err := gm.allowed(); !allowed || err != nil {
+		gm.filtered = true
+		return nil, err
+	}
+	if buf, ok, err := gm.renderTemplate(); ok {
+		return buf, err
+	}
+	l := gm.localizer()
 	switch event := gm.event.(type) {
+	case *branchProtectionRuleEvent:
+		return buildBranchProtectionRuleEvent(l, event)
 	case *commitCommentEvent:
-		return buildCommitCommentEvent(event)
+		return gm.buildCommitCommentEvent(l, event)
 	case *createEvent:
-		return buildCreateEvent(event)
+		return buildCreateEvent(l, event)
 	case *deleteEvent:
-		return buildDeleteEvent(event)
+		return buildDeleteEvent(l, event)
+	case *deploymentStatusEvent:
+		return buildDeploymentStatusEvent(l, event)
+	case *forkEvent:
+		return buildForkEvent(l, event)
+	case *gollumEvent:
+		return buildGollumEvent(l, event)
 	case *issueCommentEvent:
-		return buildIssueCommentEvent(event)
+		return gm.buildIssueCommentEvent(l, event)
 	case *issuesEvent:
-		return buildIssuesEvent(event)
+		return gm.buildIssuesEvent(l, event)
+	case *labelEvent:
+		return buildLabelEvent(l, event)
+	case *marketplacePurchaseEvent:
+		return buildMarketplacePurchaseEvent(l, event)
+	case *memberEvent:
+		return buildMemberEvent(l, event)
+	case *membershipEvent:
+		return buildMembershipEvent(l, event)
+	case *mergeGroupEvent:
+		return buildMergeGroupEvent(l, event)
+	case *milestoneEvent:
+		return gm.buildMilestoneEvent(l, event)
+	case *packageEvent:
+		return gm.buildPackageEvent(l, event)
+	case *pageBuildEvent:
+		return buildPageBuildEvent(l, event)
+	case *pingEvent:
+		return buildPingEvent(l, event)
+	case *projectsV2ItemEvent:
+		return buildProjectsV2ItemEvent(l, event)
 	case *pullRequestEvent:
-		return buildPullRequestEvent(event)
+		return gm.buildPullRequestEvent(l, event)
 	case *pullRequestReviewEvent:
-		return buildPullRequestReviewEvent(event)
+		return gm.buildPullRequestReviewEvent(l, event)
 	case *pullRequestReviewCommentEvent:
-		return buildPullRequestReviewCommentEvent(event)
+		return gm.buildPullRequestReviewCommentEvent(l, event)
 	case *pullRequestTargetEvent:
-		return buildPullRequestTargetEvent(event)
+		return gm.buildPullRequestTargetEvent(l, event)
 	case *pushEvent:
-		return buildPushEvent(event)
+		return gm.buildPushEvent(l, event)
+	case *repositoryEvent:
+		return buildRepositoryEvent(l, event)
+	case *repositoryRulesetEvent:
+		return buildRepositoryRulesetEvent(l, event)
+	case *securityAlertEvent:
+		return buildSecurityAlertEvent(l, gm.eventType, event)
+	case *sponsorshipEvent:
+		return buildSponsorshipEvent(l, event)
+	case *starEvent:
+		return gm.buildStarEvent(l, event)
+	case *teamAddEvent:
+		return buildTeamAddEvent(l, event)
+	case *teamEvent:
+		return buildTeamEvent(l, event)
+	case *watchEvent:
+		return gm.buildWatchEvent(l, event)
 	default:
+		fmt.Printf("Unhandled Event: %s (%T)\n", gm.eventType, event)
+		if gm.renderUnhandled {
+			return buildGenericEvent(l, gm.eventType, event)
+		}
 		return nil, nil
 	}
 }
 
+// builder で設定により通知しないと判断した場合 (Digest でも記録しない)
+func (gm *GitHubMessage) suppressed() (*bytes.Buffer, error) {
+	gm.filtered = true
+	return nil, nil
+}
+
+// filter.rules (もしくはレポジトリの設定ファイルのルール) で除外する場合は false を返す
+func (gm *GitHubMessage) allowed() (bool, error) {
+	f := gm.activeFilter()
+	if f == nil {
+		return true, nil
+	}
+	data, err := eventData(gm.event)
+	if err != nil {
+		return false, err
+	}
+	return f.Allow(filterEvent(gm.eventType, data)), nil
+}
+
+func filterEvent(eventType string, data map[string]interface{}) *filter.Event {
+	e := &filter.Event{
+		Sender: jsonPathString(data, "sender.login"),
+		SenderType: jsonPathString(data, "sender.type"),
+		Event: eventType,
+		Action: jsonPathString(data, "action"),
+		Ref: jsonPathString(data, "ref"),
+		Repository: jsonPathString(data, "repository.full_name"),
+	}
+	switch eventType {
+	case "create", "delete":
+		// ref はブランチ名・タグ名のみ
+		switch jsonPathString(data, "ref_type") {
+		case "branch":
+			e.Ref = "refs/heads/" + e.Ref
+		case "tag":
+			e.Ref = "refs/tags/" + e.Ref
+		}
+	case "pull_request", "pull_request_review", "pull_request_review_comment", "pull_request_target":
+		// マージ先のブランチ
+		if base := jsonPathString(data, "pull_request.base.ref"); base != "" {
+			e.Ref = "refs/heads/" + base
+		}
+	}
+	if name := jsonPathString(data, "label.name"); name != "" {
+		e.Labels = append(e.Labels, name)
+	}
+	for _, key := range []string{"issue", "pull_request"} {
+		obj, _ := data[key].(map[string]interface{})
+		labels, _ := obj["labels"].([]interface{})
+		for _, label := range labels {
+			if l, ok := label.(map[string]interface{}); ok {
+				e.Labels = append(e.Labels, jsonPathString(l, "name"))
+			}
+		}
+	}
+	// push で変更されたファイル
+	commits, _ := data["commits"].([]interface{})
+	for _, commit := range commits {
+		c, _ := commit.(map[string]interface{})
+		for _, key := range []string{"added", "removed", "modified"} {
+			paths, _ := c[key].([]interface{})
+			for _, p := range paths {
+				if s, ok := p.(string); ok {
+					e.Paths = append(e.Paths, s)
+				}
+			}
+		}
+	}
+	return e
+}
+
+// テンプレートが用意されている Event は組み込みの builder より優先する
+func (gm *GitHubMessage) renderTemplate() (*bytes.Buffer, bool, error) {
+	if gm.templates == nil {
+		return nil, false, nil
+	}
+	data, err := eventData(gm.event)
+	if err != nil {
+		return nil, true, err
+	}
+	action := jsonPathString(data, "action")
+	if !gm.templates.Has(gm.eventType, action) {
+		return nil, false, nil
+	}
+	mention := func(login string) string {
+		return gm.mention(commitAuthor{login: login})
+	}
+	buf, err := gm.templates.Render(gm.localizer(), gm.eventType, action, data, mention)
+	return buf, true, err
+}
+
+// 権限やブランチ保護に関わる Event は、設定されていれば監査用の route へ通知する
+func (gm *GitHubMessage) Route() string {
+	switch gm.event.(type) {
+	case *branchProtectionRuleEvent, *memberEvent, *membershipEvent,
+		*repositoryEvent, *repositoryRulesetEvent, *teamAddEvent, *teamEvent:
+		return gm.routes.Audit
+	// 金額を含むため、設定されていれば非公開の route へ通知する
+	case *marketplacePurchaseEvent, *sponsorshipEvent:
+		return gm.routes.Sponsor
+	}
+	// レポジトリの設定ファイルで指定されている route
+	if gm.repo != nil {
+		return gm.repo.route
+	}
+	return ""
+}
+
+// セキュリティのアラートが解決された (もしくは取り下げられた) action
+var resolvedAlertActions = map[string]bool{
+	"auto_dismissed": true,
+	"closed_by_user": true,
+	"dismissed": true,
+	"fixed": true,
+	"resolve": true,
+	"resolved": true,
+	"withdrawn": true,
+}
+
+// セキュリティに関わる Event・本番環境へのデプロイの失敗は、通知を控える時間帯でも通知する
+func (gm *GitHubMessage) Urgent() bool {
+	data, err := eventData(gm.event)
+	if err != nil {
+		return false
+	}
+	action := jsonPathString(data, "action")
+	switch gm.eventType {
+	case "code_scanning_alert", "dependabot_alert", "repository_vulnerability_alert", "secret_scanning_alert", "security_advisory":
+		// 解決された場合は急がない
+		if !resolvedAlertActions[action] {
+			return true
+		}
+	case "deployment_status":
+		state := jsonPathString(data, "deployment_status.state")
+		env := strings.ToLower(jsonPathString(data, "deployment_status.environment"))
+		if (state == "failure" || state == "error") && (env == "production" || env == "prod") {
+			return true
+		}
+	}
+	e := filterEvent(gm.eventType, data)
+	for _, r := range gm.urgentRules {
+		if r.Match(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// ダイジェストに記録する Event の概要 (Event がない・除外した場合は nil)
+// builder がない等で通知しない Event (release 等) も、除外していなければ記録する
+func (gm *GitHubMessage) Digest() *builder.DigestEntry {
+	if gm.filtered {
+		return nil
+	}
+	data, err := eventData(gm.event)
+	if err != nil || data == nil {
+		return nil
+	}
+	e := &builder.DigestEntry{
+		Event: gm.eventType,
+		Action: jsonPathString(data, "action"),
+		Sender: jsonPathString(data, "sender.login"),
+		SenderType: jsonPathString(data, "sender.type"),
+	}
+	switch gm.eventType {
+	case "pull_request":
+		if pr, ok := data["pull_request"].(map[string]interface{}); ok && e.Action == "closed" && pr["merged"] == true {
+			e.Action = "merged"
+		}
+		e.Title = jsonPathString(data, "pull_request.title")
+		e.URL = jsonPathString(data, "pull_request.html_url")
+	case "issues":
+		e.Title = jsonPathString(data, "issue.title")
+		e.URL = jsonPathString(data, "issue.html_url")
+	case "release":
+		e.Title = jsonPathString(data, "release.name")
+		if e.Title == "" {
+			e.Title = jsonPathString(data, "release.tag_name")
+		}
+		e.URL = jsonPathString(data, "release.html_url")
+	}
+	return e
+}
+
+// 本文やコメントの GitHub Markdown を通知先の形式に変換する
+func (gm *GitHubMessage) markdown(s string) string {
+	return markdown.Convert(s, gm.markdownFormat)
+}
+
+// 通知先の route の言語
+func (gm *GitHubMessage) localizer() *i18n.Localizer {
+	return i18n.NewLocalizer(i18n.RouteLocale(gm.Route()))
+}
+
 func (gm *GitHubMessage) ToDummyPayload() (*bytes.Buffer, error) {
+	l := gm.localizer()
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), "Bot", true)
+	a.InsertField(l.T("field.action"), "Invoke", true)
+	a.InsertField(l.T("field.body"), "OK")
+	return a.Build()
+}
+
+func buildBranchProtectionRuleEvent(l *i18n.Localizer, e *branchProtectionRuleEvent) (*bytes.Buffer, error) {
 	a := builder.NewAttachment()
-	a.InsertField("アカウント", "Bot", true)
-	a.InsertField("アクション", "Invoke", true)
-	a.InsertField("内容", "OK")
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	switch e.GetAction() {
+	case "created":
+		a.InsertField(l.T("field.action"), l.T("branch_protection_rule.created"), true)
+		a.InsertField(l.T("field.target_branch"), e.GetRule().GetName())
+		a.InsertField(l.T("field.settings"), branchProtectionRuleSummary(l, e.GetRule()))
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	case "edited":
+		a.InsertField(l.T("field.action"), l.T("branch_protection_rule.edited"), true)
+		a.InsertField(l.T("field.target_branch"), e.GetRule().GetName())
+		if len(e.Changes) > 0 {
+			keys := make([]string, 0, len(e.Changes))
+			for k := range e.Changes {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			var b strings.Builder
+			for _, k := range keys {
+				b.WriteString(fmt.Sprintf("%s: %s\n", k, e.Changes[k].GetFrom()))
+			}
+			a.InsertField(l.T("field.settings_from"), b.String())
+		}
+		a.InsertField(l.T("field.settings_to"), branchProtectionRuleSummary(l, e.GetRule()))
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	case "deleted":
+		a.InsertField(l.T("field.action"), l.T("branch_protection_rule.deleted"), true)
+		a.InsertField(l.T("field.target_branch"), e.GetRule().GetName())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("BranchProtectionRuleEvent (%s)", e.GetAction()))
+	}
 	return a.Build()
 }
 
-func buildCommitCommentEvent(e *commitCommentEvent) (*bytes.Buffer, error) {
+func branchProtectionRuleSummary(l *i18n.Localizer, r *branchProtectionRule) string {
+	var b strings.Builder
+	b.WriteString(l.T("branch_protection_rule.required_reviews", r.GetRequiredApprovingReviewCount()))
+	b.WriteString(l.T("branch_protection_rule.code_owner_review", r.GetRequireCodeOwnerReview()))
+	b.WriteString(l.T("branch_protection_rule.enforce_admins", r.GetAdminEnforced()))
+	if r != nil && len(r.RequiredStatusChecks) > 0 {
+		b.WriteString(l.T("branch_protection_rule.required_status_checks", strings.Join(r.RequiredStatusChecks, ", ")))
+	}
+	b.WriteString(l.T("branch_protection_rule.allow_force_pushes", r.GetAllowForcePushesEnforcementLevel()))
+	b.WriteString(l.T("branch_protection_rule.allow_deletions", r.GetAllowDeletionsEnforcementLevel()))
+	return b.String()
+}
+
+func (gm *GitHubMessage) buildCommitCommentEvent(l *i18n.Localizer, e *commitCommentEvent) (*bytes.Buffer, error) {
 	a := builder.NewAttachment()
-	a.InsertField("アカウント", e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
 	switch e.GetAction() {
 	case "created":
-		a.InsertField("アクション", "コメントされました", true)
-		a.InsertField("コメント", e.GetComment().GetBody())
-		a.InsertField("CommitID", e.GetComment().GetCommitID())
-		a.InsertField("リンク", e.GetComment().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("commit_comment.created"), true)
+		a.InsertField(l.T("field.comment"), gm.markdown(e.GetComment().GetBody()))
+		a.InsertField(l.T("field.commit_id"), e.GetComment().GetCommitID())
+		a.InsertField(l.T("field.link"), e.GetComment().GetHTMLURL())
 	default:
-		a.InsertField("アクション", fmt.Sprintf("CommitCommentEvent (%s)", e.GetAction()))
+		a.InsertField(l.T("field.action"), fmt.Sprintf("CommitCommentEvent (%s)", e.GetAction()))
 	}
 	return a.Build()
 }
 
-func buildCreateEvent(e *createEvent) (*bytes.Buffer, error) {
+func buildCreateEvent(l *i18n.Localizer, e *createEvent) (*bytes.Buffer, error) {
 	a := builder.NewAttachment()
-	a.InsertField("アカウント", e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
 	switch e.GetRefType() {
 	case "branch":
-		a.InsertField("アクション", "ブランチが作成されました", true)
-		a.InsertField("ブランチ名", e.GetRef())
-		a.InsertField("リンク", e.GetRepo().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("create.branch"), true)
+		a.InsertField(l.T("field.branch_name"), e.GetRef())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
 	case "tag":
-		a.InsertField("アクション", "タグが作成されました", true)
-		a.InsertField("タグ名", e.GetRef())
-		a.InsertField("リンク", e.GetRepo().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("create.tag"), true)
+		a.InsertField(l.T("field.tag_name"), e.GetRef())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
 	}
 	return a.Build()
 }
 
-func buildDeleteEvent(e *deleteEvent) (*bytes.Buffer, error) {
+func buildDeleteEvent(l *i18n.Localizer, e *deleteEvent) (*bytes.Buffer, error) {
 	a := builder.NewAttachment()
-	a.InsertField("アカウント", e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
 	switch e.GetRefType() {
 	case "branch":
-		a.InsertField("アクション", "ブランチが削除されました", true)
-		a.InsertField("ブランチ名", e.GetRef())
-		a.InsertField("リンク", e.GetRepo().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("delete.branch"), true)
+		a.InsertField(l.T("field.branch_name"), e.GetRef())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
 	case "tag":
-		a.InsertField("アクション", "タグが削除されました", true)
-		a.InsertField("タグ名", e.GetRef())
-		a.InsertField("リンク", e.GetRepo().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("delete.tag"), true)
+		a.InsertField(l.T("field.tag_name"), e.GetRef())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	}
+	return a.Build()
+}
+
+func buildDeploymentStatusEvent(l *i18n.Localizer, e *deploymentStatusEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	status := e.GetDeploymentStatus()
+	switch status.GetState() {
+	case "success":
+		a.InsertField(l.T("field.action"), l.T("deployment_status.success"), true)
+	case "failure", "error":
+		a.SetColor(builder.ErrorColor)
+		a.InsertField(l.T("field.action"), l.T("deployment_status.failure"), true)
+	// 完了していない場合は通知しない
+	case "pending", "queued", "in_progress", "inactive":
+		return nil, nil
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("DeploymentStatusEvent (%s)", status.GetState()))
+		return a.Build()
+	}
+	a.InsertField(l.T("field.environment"), status.GetEnvironment(), true)
+	a.InsertField(l.T("field.ref"), e.GetDeployment().GetRef(), true)
+	if d := status.GetDescription(); d != "" {
+		a.InsertField(l.T("field.description"), d)
+	}
+	a.InsertField(l.T("field.link"), deploymentHTMLURL(e))
+	return a.Build()
+}
+
+// ログ・デプロイ先がない場合はレポジトリのデプロイ一覧
+func deploymentHTMLURL(e *deploymentStatusEvent) string {
+	status := e.GetDeploymentStatus()
+	if url := firstString(status.LogURL, status.TargetURL); url != "" {
+		return url
+	}
+	if e.GetRepo().GetHTMLURL() == "" {
+		return ""
+	}
+	return e.GetRepo().GetHTMLURL() + "/deployments"
+}
+
+func buildForkEvent(l *i18n.Localizer, e *forkEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.action"), l.T("fork"), true)
+	a.InsertField(l.T("field.fork_to"), e.GetForkee().GetFullName())
+	a.InsertField(l.T("field.forks"), strconv.Itoa(e.GetRepo().GetForksCount()), true)
+	a.InsertField(l.T("field.link"), e.GetForkee().GetHTMLURL())
+	return a.Build()
+}
+
+func buildGollumEvent(l *i18n.Localizer, e *gollumEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.action"), l.T("gollum"), true)
+	var b strings.Builder
+	for _, p := range e.Pages {
+		switch p.GetAction() {
+		case "created":
+			b.WriteString(l.T("gollum.created_page", p.GetHTMLURL(), p.GetTitle()))
+		case "edited":
+			b.WriteString(l.T("gollum.edited_page",
+				p.GetHTMLURL(), p.GetTitle(), p.GetHTMLURL(), p.GetSHA(),
+			))
+		default:
+			b.WriteString(fmt.Sprintf("[%s] <%s|%s>", p.GetAction(), p.GetHTMLURL(), p.GetTitle()))
+		}
+		if summary := p.GetSummary(); summary != "" {
+			b.WriteString(fmt.Sprintf(" %s", summary))
+		}
+		b.WriteString("\n")
 	}
+	a.InsertField(l.T("field.pages"), b.String())
+	a.InsertField(l.T("field.link"), wikiHTMLURL(e.GetRepo()))
 	return a.Build()
 }
 
-func buildIssueCommentEvent(e *issueCommentEvent) (*bytes.Buffer, error) {
+func wikiHTMLURL(repo *github.Repository) string {
+	if repo.GetHTMLURL() == "" {
+		return ""
+	}
+	return repo.GetHTMLURL() + "/wiki"
+}
+
+func (gm *GitHubMessage) buildIssueCommentEvent(l *i18n.Localizer, e *issueCommentEvent) (*bytes.Buffer, error) {
 	a := builder.NewAttachment()
-	a.InsertField("アカウント", e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
 	switch e.GetAction() {
 	case "created":
-		a.InsertField("アクション", "コメントされました", true)
-		a.InsertField("コメント", e.GetComment().GetBody())
-		a.InsertField("リンク", e.GetComment().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issue_comment.created"), true)
+		a.InsertField(l.T("field.comment"), gm.markdown(e.GetComment().GetBody()))
+		a.InsertField(l.T("field.link"), e.GetComment().GetHTMLURL())
 	case "edited":
-		a.InsertField("アクション", "コメントが変更されました", true)
+		a.InsertField(l.T("field.action"), l.T("issue_comment.edited"), true)
 		if body := e.GetChanges().GetBody(); body != nil {
-			a.InsertField("コメント(変更前)", body.GetFrom())
-			a.InsertField("コメント(変更後)", e.GetComment().GetBody())
+			a.InsertField(l.T("field.comment_from"), gm.markdown(body.GetFrom()))
+			a.InsertField(l.T("field.comment_to"), gm.markdown(e.GetComment().GetBody()))
 		}
-		a.InsertField("リンク", e.GetComment().GetHTMLURL())
+		a.InsertField(l.T("field.link"), e.GetComment().GetHTMLURL())
 	case "deleted":
-		a.InsertField("アクション", "コメントが削除されました", true)
-		a.InsertField("コメント", e.GetComment().GetBody())
-		a.InsertField("リンク", e.GetComment().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issue_comment.deleted"), true)
+		a.InsertField(l.T("field.comment"), gm.markdown(e.GetComment().GetBody()))
+		a.InsertField(l.T("field.link"), e.GetComment().GetHTMLURL())
 	default:
-		a.InsertField("アクション", fmt.Sprintf("IssueCommentEvent (%s)", e.GetAction()))
+		a.InsertField(l.T("field.action"), fmt.Sprintf("IssueCommentEvent (%s)", e.GetAction()))
 	}
 	return a.Build()
 }
 
-func buildIssuesEvent(e *issuesEvent) (*bytes.Buffer, error) {
+func (gm *GitHubMessage) buildIssuesEvent(l *i18n.Localizer, e *issuesEvent) (*bytes.Buffer, error) {
 	a := builder.NewAttachment()
-	a.InsertField("アカウント", e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
 	switch e.GetAction() {
 	case "opened":
-		a.InsertField("アクション", "Issue がオープンされました", true)
-		a.InsertField("タイトル", e.GetIssue().GetTitle())
-		a.InsertField("内容", e.GetIssue().GetBody())
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.opened"), true)
+		a.InsertField(l.T("field.title"), e.GetIssue().GetTitle())
+		a.InsertField(l.T("field.body"), gm.markdown(e.GetIssue().GetBody()))
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
 	case "edited":
-		a.InsertField("アクション", "Issue が編集されました", true)
+		a.InsertField(l.T("field.action"), l.T("issues.edited"), true)
 		if title := e.GetChanges().GetTitle(); title != nil {
-			a.InsertField("タイトル(変更前)", title.GetFrom())
-			a.InsertField("タイトル(変更後)", e.GetIssue().GetTitle())
+			a.InsertField(l.T("field.title_from"), title.GetFrom())
+			a.InsertField(l.T("field.title_to"), e.GetIssue().GetTitle())
 		}
 		if body := e.GetChanges().GetBody(); body != nil {
-			a.InsertField("内容(変更前)", body.GetFrom())
-			a.InsertField("内容(変更後)", e.GetIssue().GetBody())
+			a.InsertField(l.T("field.body_from"), gm.markdown(body.GetFrom()))
+			a.InsertField(l.T("field.body_to"), gm.markdown(e.GetIssue().GetBody()))
 		}
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
 	case "deleted":
-		a.InsertField("アクション", "Issue が削除されました", true)
-		a.InsertField("タイトル", e.GetIssue().GetTitle())
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.deleted"), true)
+		a.InsertField(l.T("field.title"), e.GetIssue().GetTitle())
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
 	case "closed":
-		a.InsertField("アクション", "Issue がクローズされました", true)
-		a.InsertField("タイトル", e.GetIssue().GetTitle())
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.closed"), true)
+		a.InsertField(l.T("field.title"), e.GetIssue().GetTitle())
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
 	case "reopened":
-		a.InsertField("アクション", "Issue が再オープンされました", true)
-		a.InsertField("タイトル", e.GetIssue().GetTitle())
-		a.InsertField("内容", e.GetIssue().GetBody())
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.reopened"), true)
+		a.InsertField(l.T("field.title"), e.GetIssue().GetTitle())
+		a.InsertField(l.T("field.body"), gm.markdown(e.GetIssue().GetBody()))
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
 	case "assigned":
-		a.InsertField("アクション", "Issue にアサインされました", true)
-		a.InsertField("対象者", e.GetAssignee().GetLogin())
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.assigned"), true)
+		a.InsertField(l.T("field.target_user"), e.GetAssignee().GetLogin())
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
 	case "unassigned":
-		a.InsertField("アクション", "Issue にアンアサインされました", true)
-		a.InsertField("対象者", e.GetAssignee().GetLogin())
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.unassigned"), true)
+		a.InsertField(l.T("field.target_user"), e.GetAssignee().GetLogin())
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
 	case "labeled":
-		a.InsertField("アクション", "Issue にラベルが付与されました", true)
-		a.InsertField("ラベル", e.GetLabel().GetName())
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.labeled"), true)
+		a.InsertField(l.T("field.label"), e.GetLabel().GetName())
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
 	case "unlabeled":
-		a.InsertField("アクション", "Issue のラベルが外されました", true)
-		a.InsertField("ラベル", e.GetLabel().GetName())
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.unlabeled"), true)
+		a.InsertField(l.T("field.label"), e.GetLabel().GetName())
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
 	case "locked":
-		a.InsertField("アクション", "Issue がロックされました", true)
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.locked"), true)
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
 	case "unlocked":
-		a.InsertField("アクション", "Issue のロックが解除されました", true)
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.unlocked"), true)
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
 	case "pinned":
-		a.InsertField("アクション", "Issue がピン留めされました", true)
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.pinned"), true)
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
 	case "unpinned":
-		a.InsertField("アクション", "Issue のピン留めが解除されました", true)
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
-	case "transferred": // TODO: 譲渡先の URL を取得する
-		a.InsertField("アクション", "Issue が譲渡されました", true)
-		a.InsertField("リンク(譲渡前)", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.unpinned"), true)
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
+	case "transferred":
+		a.InsertField(l.T("field.action"), l.T("issues.transferred"), true)
+		a.InsertField(l.T("field.title"), e.GetIssue().GetTitle())
+		newIssue, newRepo := e.GetNewIssue(), e.GetNewRepository()
+		if newIssue == nil {
+			newIssue = gm.getTransferredIssue(e)
+		}
+		if newRepo == nil && newIssue != nil {
+			newRepo = newIssue.GetRepository()
+		}
+		if name := transferredRepository(newRepo, newIssue); name != "" {
+			a.InsertField(l.T("field.transferred_to"), name, true)
+		}
+		a.InsertField(l.T("field.link_from"), e.GetIssue().GetHTMLURL())
+		if url := newIssue.GetHTMLURL(); url != "" {
+			a.InsertField(l.T("field.link_to"), url)
+		}
 	case "milestoned":
-		a.InsertField("アクション", "マイルストーンが設定されました", true)
-		a.InsertField("マイルストーン", e.GetIssue().GetMilestone().GetTitle())
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.milestoned"), true)
+		a.InsertField(l.T("field.milestone"), e.GetIssue().GetMilestone().GetTitle())
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
 	case "demilestoned":
-		a.InsertField("アクション", "マイルストーンが解除されました", true)
-		a.InsertField("リンク", e.GetIssue().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("issues.demilestoned"), true)
+		a.InsertField(l.T("field.link"), e.GetIssue().GetHTMLURL())
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("IssuesEvent (%s)", e.GetAction()))
+	}
+	return a.Build()
+}
+
+// Webhook に譲渡先が含まれない場合は API から取得する (取得できない場合は nil)
+func (gm *GitHubMessage) getTransferredIssue(e *issuesEvent) *github.Issue {
+	if gm.api == nil {
+		return nil
+	}
+	issue, err := gm.api.GetIssue(
+		e.GetRepo().GetOwner().GetLogin(), e.GetRepo().GetName(), e.GetIssue().GetNumber(),
+	)
+	if err != nil {
+		fmt.Printf("Get Issue Failed: %v\n", err)
+		return nil
+	}
+	// リダイレクトされていない場合は譲渡先が分からない
+	if issue.GetHTMLURL() == e.GetIssue().GetHTMLURL() {
+		return nil
+	}
+	return issue
+}
+
+// Issue の API レスポンスは repository を含まないため、URL から求める
+func transferredRepository(repo *github.Repository, issue *github.Issue) string {
+	if repo.GetFullName() != "" {
+		return fmt.Sprintf("<%s|%s>", repo.GetHTMLURL(), repo.GetFullName())
+	}
+	u := issue.GetHTMLURL()
+	if i := strings.Index(u, "/issues/"); i >= 0 {
+		repoURL := u[:i]
+		parts := strings.Split(repoURL, "/")
+		if len(parts) >= 2 {
+			return fmt.Sprintf("<%s|%s/%s>", repoURL, parts[len(parts)-2], parts[len(parts)-1])
+		}
+	}
+	return ""
+}
+
+func buildLabelEvent(l *i18n.Localizer, e *labelEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	switch e.GetAction() {
+	case "created":
+		a.InsertField(l.T("field.action"), l.T("label.created"), true)
+		a.InsertField(l.T("field.label"), e.GetLabel().GetName(), true)
+		a.InsertField(l.T("field.color"), labelColor(e.GetLabel().GetColor()), true)
+		a.InsertField(l.T("field.description"), e.GetLabel().GetDescription())
+		a.InsertField(l.T("field.link"), labelsHTMLURL(e.GetRepo()))
+	case "edited":
+		a.InsertField(l.T("field.action"), l.T("label.edited"), true)
+		if name := e.GetChanges().GetName(); name != nil {
+			a.InsertField(l.T("field.label_from"), name.GetFrom(), true)
+			a.InsertField(l.T("field.label_to"), e.GetLabel().GetName(), true)
+		} else {
+			a.InsertField(l.T("field.label"), e.GetLabel().GetName(), true)
+		}
+		if color := e.GetChanges().GetColor(); color != nil {
+			a.InsertField(l.T("field.color_from"), labelColor(color.GetFrom()), true)
+			a.InsertField(l.T("field.color_to"), labelColor(e.GetLabel().GetColor()), true)
+		}
+		if description := e.GetChanges().GetDescription(); description != nil {
+			a.InsertField(l.T("field.description_from"), description.GetFrom())
+			a.InsertField(l.T("field.description_to"), e.GetLabel().GetDescription())
+		}
+		a.InsertField(l.T("field.link"), labelsHTMLURL(e.GetRepo()))
+	case "deleted":
+		a.InsertField(l.T("field.action"), l.T("label.deleted"), true)
+		a.InsertField(l.T("field.label"), e.GetLabel().GetName(), true)
+		a.InsertField(l.T("field.link"), labelsHTMLURL(e.GetRepo()))
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("LabelEvent (%s)", e.GetAction()))
+	}
+	return a.Build()
+}
+
+func labelColor(color string) string {
+	if color == "" {
+		return ""
+	}
+	return "#" + color
+}
+
+func labelsHTMLURL(repo *github.Repository) string {
+	if repo.GetHTMLURL() == "" {
+		return ""
+	}
+	return repo.GetHTMLURL() + "/labels"
+}
+
+func buildMarketplacePurchaseEvent(l *i18n.Localizer, e *marketplacePurchaseEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	p := e.GetMarketplacePurchase()
+	a.InsertField(l.T("field.account"), p.GetAccount().GetLogin(), true)
+	switch e.GetAction() {
+	case "purchased":
+		a.SetColor(builder.CelebrationColor)
+		a.InsertField(l.T("field.action"), l.T("marketplace_purchase.purchased"), true)
+	case "changed":
+		a.InsertField(l.T("field.action"), l.T("marketplace_purchase.changed"), true)
+	case "pending_change":
+		a.InsertField(l.T("field.action"), l.T("marketplace_purchase.pending_change"), true)
+	case "pending_change_cancelled":
+		a.InsertField(l.T("field.action"), l.T("marketplace_purchase.pending_change_cancelled"), true)
+	case "cancelled":
+		a.SetColor(builder.WarningColor)
+		a.InsertField(l.T("field.action"), l.T("marketplace_purchase.cancelled"), true)
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("MarketplacePurchaseEvent (%s)", e.GetAction()))
+		return a.Build()
+	}
+	a.InsertField(l.T("field.plan"), marketplacePlan(l, &p.MarketplacePurchase))
+	if prev := e.GetPreviousMarketplacePurchase(); prev != nil {
+		a.InsertField(l.T("field.before"), marketplacePlan(l, &prev.MarketplacePurchase))
+	}
+	if e.EffectiveDate != nil {
+		a.InsertField(l.T("field.effective_date"), e.GetEffectiveDate().UTC().Format("2006-01-02"), true)
+	}
+	if p.GetOnFreeTrial() {
+		a.InsertField(l.T("field.free_trial"), l.T("format.until", p.GetFreeTrialEndsOn().UTC().Format("2006-01-02")), true)
+	}
+	return a.Build()
+}
+
+// プラン名 ($金額 / 期間)、ユニット単位の場合は数量も付ける
+func marketplacePlan(l *i18n.Localizer, p *github.MarketplacePurchase) string {
+	plan := p.GetPlan()
+	price := l.T("format.per_month", formatCents(plan.GetMonthlyPriceInCents()))
+	if p.GetBillingCycle() == "yearly" {
+		price = l.T("format.per_year", formatCents(plan.GetYearlyPriceInCents()))
+	}
+	s := fmt.Sprintf("%s (%s)", plan.GetName(), price)
+	if plan.GetPriceModel() == "per-unit" {
+		s += l.T("format.units", p.GetUnitCount(), plan.GetUnitName())
+	}
+	return s
+}
+
+func formatCents(cents int) string {
+	if cents%100 == 0 {
+		return fmt.Sprintf("$%d", cents/100)
+	}
+	return fmt.Sprintf("$%d.%02d", cents/100, cents%100)
+}
+
+func buildMemberEvent(l *i18n.Localizer, e *memberEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	switch e.GetAction() {
+	case "added":
+		a.InsertField(l.T("field.action"), l.T("member.added"), true)
+		a.InsertField(l.T("field.target_user"), e.GetMember().GetLogin())
+		a.InsertField(l.T("field.permission"), e.GetPermissionTo())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	case "edited":
+		a.InsertField(l.T("field.action"), l.T("member.edited"), true)
+		a.InsertField(l.T("field.target_user"), e.GetMember().GetLogin())
+		a.InsertField(l.T("field.permission_from"), e.GetPermissionFrom(), true)
+		a.InsertField(l.T("field.permission_to"), e.GetPermissionTo(), true)
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	case "removed":
+		a.InsertField(l.T("field.action"), l.T("member.removed"), true)
+		a.InsertField(l.T("field.target_user"), e.GetMember().GetLogin())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
 	default:
-		a.InsertField("アクション", fmt.Sprintf("IssuesEvent (%s)", e.GetAction()))
+		a.InsertField(l.T("field.action"), fmt.Sprintf("MemberEvent (%s)", e.GetAction()))
 	}
 	return a.Build()
 }
 
-func buildPullRequestEvent(e *pullRequestEvent) (*bytes.Buffer, error) {
+func buildMembershipEvent(l *i18n.Localizer, e *membershipEvent) (*bytes.Buffer, error) {
 	a := builder.NewAttachment()
-	a.InsertField("アカウント", e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
 	switch e.GetAction() {
+	case "added":
+		a.InsertField(l.T("field.action"), l.T("membership.added"), true)
+		a.InsertField(l.T("field.team"), e.GetTeam().GetName())
+		a.InsertField(l.T("field.target_user"), e.GetMember().GetLogin())
+		a.InsertField(l.T("field.link"), teamHTMLURL(e.GetOrg(), e.GetTeam()))
+	case "removed":
+		a.InsertField(l.T("field.action"), l.T("membership.removed"), true)
+		a.InsertField(l.T("field.team"), e.GetTeam().GetName())
+		a.InsertField(l.T("field.target_user"), e.GetMember().GetLogin())
+		a.InsertField(l.T("field.link"), teamHTMLURL(e.GetOrg(), e.GetTeam()))
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("MembershipEvent (%s)", e.GetAction()))
+	}
+	return a.Build()
+}
+
+func buildMergeGroupEvent(l *i18n.Localizer, e *mergeGroupEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	g := e.GetMergeGroup()
+	switch e.GetAction() {
+	case "checks_requested":
+		a.InsertField(l.T("field.action"), l.T("merge_group.checks_requested"), true)
+	case "destroyed":
+		switch e.GetReason() {
+		case "merged":
+			a.InsertField(l.T("field.action"), l.T("merge_group.merged"), true)
+		case "invalidated":
+			a.SetColor(builder.WarningColor)
+			a.InsertField(l.T("field.action"), l.T("merge_group.invalidated"), true)
+		case "dequeued":
+			a.SetColor(builder.ErrorColor)
+			a.InsertField(l.T("field.action"), l.T("merge_group.dequeued"), true)
+		default:
+			a.InsertField(l.T("field.action"), l.T("merge_group.destroyed"), true)
+		}
+		if reason := e.GetReason(); reason != "" {
+			a.InsertField(l.T("field.reason"), reason, true)
+		}
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("MergeGroupEvent (%s)", e.GetAction()))
+		return a.Build()
+	}
+	a.InsertField(l.T("field.branch"), strings.TrimPrefix(g.GetBaseRef(), "refs/heads/"), true)
+	if pr := mergeGroupPullRequest(e.GetRepo(), g.GetHeadRef()); pr != "" {
+		a.InsertField(l.T("field.pull_request"), pr, true)
+	}
+	if c := g.GetHeadCommit(); c != nil {
+		a.InsertField(l.T("field.body"), c.GetMessage())
+	}
+	if sha := g.GetHeadSHA(); sha != "" {
+		a.InsertField(l.T("field.commit"), commitLink(e.GetRepo().GetHTMLURL(), sha), true)
+	}
+	return a.Build()
+}
+
+// マージキューのブランチ名から対象の PR へのリンクを作る
+func mergeGroupPullRequest(repo *github.Repository, headRef string) string {
+	m := mergeQueueRef.FindStringSubmatch(headRef)
+	if m == nil {
+		return ""
+	}
+	return fmt.Sprintf("<%s/pull/%s|#%s>", repo.GetHTMLURL(), m[1], m[1])
+}
+
+func (gm *GitHubMessage) buildMilestoneEvent(l *i18n.Localizer, e *milestoneEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	m := e.GetMilestone()
+	switch e.GetAction() {
+	case "created":
+		a.InsertField(l.T("field.action"), l.T("milestone.created"), true)
+		a.InsertField(l.T("field.milestone"), m.GetTitle())
+		a.InsertField(l.T("field.due_on"), milestoneDueOn(l, m), true)
+		a.InsertField(l.T("field.body"), gm.markdown(m.GetDescription()))
+		a.InsertField(l.T("field.link"), m.GetHTMLURL())
+	case "closed":
+		a.InsertField(l.T("field.action"), l.T("milestone.closed"), true)
+		a.InsertField(l.T("field.milestone"), m.GetTitle())
+		a.InsertField(l.T("field.open_issues"), strconv.Itoa(m.GetOpenIssues()), true)
+		a.InsertField(l.T("field.closed_issues"), strconv.Itoa(m.GetClosedIssues()), true)
+		a.InsertField(l.T("field.progress"), milestoneProgress(m), true)
+		a.InsertField(l.T("field.due_on"), milestoneDueOn(l, m), true)
+		a.InsertField(l.T("field.link"), m.GetHTMLURL())
 	case "opened":
-		a.InsertField("アクション", "PR がオープンされました", true)
-		a.InsertField("タイトル", e.GetPullRequest().GetTitle())
-		a.InsertField("内容", e.GetPullRequest().GetBody())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("milestone.opened"), true)
+		a.InsertField(l.T("field.milestone"), m.GetTitle())
+		a.InsertField(l.T("field.open_issues"), strconv.Itoa(m.GetOpenIssues()), true)
+		a.InsertField(l.T("field.closed_issues"), strconv.Itoa(m.GetClosedIssues()), true)
+		a.InsertField(l.T("field.due_on"), milestoneDueOn(l, m), true)
+		a.InsertField(l.T("field.link"), m.GetHTMLURL())
 	case "edited":
-		a.InsertField("アクション", "PR が編集されました", true)
+		a.InsertField(l.T("field.action"), l.T("milestone.edited"), true)
 		if title := e.GetChanges().GetTitle(); title != nil {
-			a.InsertField("タイトル(変更前)", title.GetFrom())
-			a.InsertField("タイトル(変更後)", e.GetPullRequest().GetTitle())
+			a.InsertField(l.T("field.milestone_from"), title.GetFrom())
+			a.InsertField(l.T("field.milestone_to"), m.GetTitle())
+		} else {
+			a.InsertField(l.T("field.milestone"), m.GetTitle())
+		}
+		if dueOn := e.GetChanges().GetDueOn(); dueOn != nil {
+			a.InsertField(l.T("field.due_on_from"), formatDate(l, dueOn.GetFrom()), true)
+			a.InsertField(l.T("field.due_on_to"), milestoneDueOn(l, m), true)
+		}
+		if description := e.GetChanges().GetDescription(); description != nil {
+			a.InsertField(l.T("field.body_from"), gm.markdown(description.GetFrom()))
+			a.InsertField(l.T("field.body_to"), gm.markdown(m.GetDescription()))
+		}
+		a.InsertField(l.T("field.link"), m.GetHTMLURL())
+	case "deleted":
+		a.InsertField(l.T("field.action"), l.T("milestone.deleted"), true)
+		a.InsertField(l.T("field.milestone"), m.GetTitle())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("MilestoneEvent (%s)", e.GetAction()))
+	}
+	return a.Build()
+}
+
+func milestoneDueOn(l *i18n.Localizer, m *github.Milestone) string {
+	if m.DueOn == nil {
+		return l.T("value.none")
+	}
+	return m.GetDueOn().UTC().Format("2006-01-02")
+}
+
+func milestoneProgress(m *github.Milestone) string {
+	total := m.GetOpenIssues() + m.GetClosedIssues()
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", m.GetClosedIssues()*100/total)
+}
+
+// RFC3339 形式の日時を日付のみにする (空の場合は "なし")
+func formatDate(l *i18n.Localizer, s string) string {
+	if s == "" {
+		return l.T("value.none")
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return t.UTC().Format("2006-01-02")
+}
+
+func (gm *GitHubMessage) buildPackageEvent(l *i18n.Localizer, e *packageEvent) (*bytes.Buffer, error) {
+	p := e.GetPackage()
+	if !gm.matchPackage(p.GetName()) {
+		return gm.suppressed()
+	}
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	switch e.GetAction() {
+	case "published":
+		a.InsertField(l.T("field.action"), l.T("package.published"), true)
+	case "updated":
+		a.InsertField(l.T("field.action"), l.T("package.updated"), true)
+	case "deleted":
+		a.SetColor(builder.WarningColor)
+		a.InsertField(l.T("field.action"), l.T("package.deleted"), true)
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("PackageEvent (%s)", e.GetAction()))
+		return a.Build()
+	}
+	a.InsertField(l.T("field.package"), fmt.Sprintf("<%s|%s>", p.GetHTMLURL(), p.GetName()), true)
+	a.InsertField(l.T("field.ecosystem"), strings.ToLower(p.GetEcosystem()), true)
+	v := p.GetPackageVersion()
+	if tag := v.GetTag(); tag != "" {
+		a.InsertField(l.T("field.version"), tag, true)
+	}
+	if author := v.GetAuthor().GetLogin(); author != "" {
+		a.InsertField(l.T("field.uploader"), author, true)
+	}
+	if digest := v.GetDigest(); digest != "" {
+		a.InsertField(l.T("field.digest"), digest)
+	}
+	link := v.GetHTMLURL()
+	if link == "" {
+		link = p.GetHTMLURL()
+	}
+	a.InsertField(l.T("field.link"), link)
+	return a.Build()
+}
+
+// message.package_filter のパターンのいずれかに一致する場合は true (未設定の場合はすべて通知する)
+func (gm *GitHubMessage) matchPackage(name string) bool {
+	if len(gm.packageFilter) == 0 {
+		return true
+	}
+	for _, p := range gm.packageFilter {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ビルド中 (building) の場合は通知しない
+func buildPageBuildEvent(l *i18n.Localizer, e *pageBuildEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetBuild().GetPusher().GetLogin(), true)
+	switch e.GetBuild().GetStatus() {
+	case "built":
+		a.InsertField(l.T("field.action"), l.T("page_build.built"), true)
+	case "errored":
+		a.SetColor(builder.ErrorColor)
+		a.InsertField(l.T("field.action"), l.T("page_build.errored"), true)
+		a.InsertField(l.T("field.error"), e.GetBuild().GetError().GetMessage())
+	case "building":
+		return nil, nil
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("PageBuildEvent (%s)", e.GetBuild().GetStatus()))
+		return a.Build()
+	}
+	if commit := e.GetBuild().GetCommit(); commit != "" {
+		a.InsertField(l.T("field.commit"), commitLink(e.GetRepo().GetHTMLURL(), commit), true)
+	}
+	a.InsertField(l.T("field.link"), pagesSettingsHTMLURL(e.GetRepo()))
+	return a.Build()
+}
+
+func pagesSettingsHTMLURL(repo *github.Repository) string {
+	if repo.GetHTMLURL() == "" {
+		return ""
+	}
+	return repo.GetHTMLURL() + "/settings/pages"
+}
+
+func buildPingEvent(l *i18n.Localizer, e *pingEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.action"), l.T("ping"), true)
+	a.InsertField(l.T("field.hook_id"), strconv.FormatInt(e.GetHookID(), 10), true)
+	link := ""
+	if repo := e.GetRepo(); repo != nil {
+		a.InsertField(l.T("field.repository"), repo.GetFullName(), true)
+		link = repo.GetHTMLURL()
+	} else if org := e.GetOrg(); org != nil {
+		a.InsertField(l.T("field.organization"), org.GetLogin(), true)
+		link = fmt.Sprintf("https://github.com/%s", org.GetLogin())
+	}
+	var events []string
+	if hook := e.GetHook(); hook != nil {
+		events = hook.Events
+	}
+	a.InsertField(l.T("field.events"), strings.Join(events, ", "))
+	if unrendered := unrenderedEvents(l, events); len(unrendered) > 0 {
+		a.SetColor(builder.WarningColor)
+		a.InsertField(l.T("field.warning"), l.T("ping.unrendered", strings.Join(unrendered, ", ")))
+	}
+	a.InsertField(l.T("field.link"), link)
+	return a.Build()
+}
+
+func unrenderedEvents(l *i18n.Localizer, events []string) []string {
+	rendered := map[string]bool{}
+	for _, e := range renderedEvents {
+		rendered[e] = true
+	}
+	var unrendered []string
+	for _, e := range events {
+		if e == "*" {
+			return []string{l.T("ping.all_events")}
+		}
+		if !rendered[e] {
+			unrendered = append(unrendered, e)
+		}
+	}
+	return unrendered
+}
+
+func buildProjectsV2ItemEvent(l *i18n.Localizer, e *projectsV2ItemEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	switch e.GetAction() {
+	case "created":
+		a.InsertField(l.T("field.action"), l.T("projects_v2_item.created"), true)
+	case "edited":
+		a.InsertField(l.T("field.action"), l.T("projects_v2_item.edited"), true)
+		if field := e.GetFieldName(); field != "" {
+			a.InsertField(l.T("field.field"), field, true)
+			a.InsertField(l.T("field.value_from"), e.GetFieldValueFrom(l), true)
+			a.InsertField(l.T("field.value_to"), e.GetFieldValueTo(l), true)
+		}
+	case "archived":
+		a.InsertField(l.T("field.action"), l.T("projects_v2_item.archived"), true)
+	case "restored":
+		a.InsertField(l.T("field.action"), l.T("projects_v2_item.restored"), true)
+	case "converted":
+		a.InsertField(l.T("field.action"), l.T("projects_v2_item.converted"), true)
+	case "reordered":
+		a.InsertField(l.T("field.action"), l.T("projects_v2_item.reordered"), true)
+	case "deleted":
+		a.InsertField(l.T("field.action"), l.T("projects_v2_item.deleted"), true)
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("ProjectsV2ItemEvent (%s)", e.GetAction()))
+		return a.Build()
+	}
+	a.InsertField(l.T("field.content_type"), e.GetItem().GetContentType(), true)
+	a.InsertField(l.T("field.link"), projectHTMLURL(e.GetOrg(), e.GetProjectNumber()))
+	return a.Build()
+}
+
+func projectHTMLURL(org *github.Organization, number int) string {
+	if org.GetLogin() == "" {
+		return ""
+	}
+	if number == 0 {
+		return fmt.Sprintf("https://github.com/orgs/%s/projects", org.GetLogin())
+	}
+	return fmt.Sprintf("https://github.com/orgs/%s/projects/%d", org.GetLogin(), number)
+}
+
+func (gm *GitHubMessage) buildPullRequestEvent(l *i18n.Localizer, e *pullRequestEvent) (*bytes.Buffer, error) {
+	if gm.suppressDraft(e.GetPullRequest(), e.GetAction()) {
+		return gm.suppressed()
+	}
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	switch e.GetAction() {
+	case "opened":
+		a.InsertField(l.T("field.action"), l.T("pull_request.opened"), true)
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		a.InsertField(l.T("field.body"), gm.markdown(e.GetPullRequest().GetBody()))
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
+	case "edited":
+		a.InsertField(l.T("field.action"), l.T("pull_request.edited"), true)
+		if title := e.GetChanges().GetTitle(); title != nil {
+			a.InsertField(l.T("field.title_from"), title.GetFrom())
+			a.InsertField(l.T("field.title_to"), e.GetPullRequest().GetTitle())
 		}
 		if body := e.GetChanges().GetBody(); body != nil {
-			a.InsertField("内容(変更前)", body.GetFrom())
-			a.InsertField("内容(変更後)", e.GetPullRequest().GetBody())
+			a.InsertField(l.T("field.body_from"), gm.markdown(body.GetFrom()))
+			a.InsertField(l.T("field.body_to"), gm.markdown(e.GetPullRequest().GetBody()))
 		}
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "closed":
-		a.InsertField("アクション", "PR がクローズされました", true)
-		a.InsertField("タイトル", e.GetPullRequest().GetTitle())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.closed"), true)
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "reopened":
-		a.InsertField("アクション", "PR が再オープンされました", true)
-		a.InsertField("タイトル", e.GetPullRequest().GetTitle())
-		a.InsertField("内容", e.GetPullRequest().GetBody())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.reopened"), true)
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		a.InsertField(l.T("field.body"), gm.markdown(e.GetPullRequest().GetBody()))
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "assigned":
-		a.InsertField("アクション", "PR にアサインされました", true)
-		a.InsertField("対象者", e.GetAssignee().GetLogin())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.assigned"), true)
+		a.InsertField(l.T("field.target_user"), e.GetAssignee().GetLogin())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "unassigned":
-		a.InsertField("アクション", "PR にアンアサインされました", true)
-		a.InsertField("対象者", e.GetAssignee().GetLogin())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.unassigned"), true)
+		a.InsertField(l.T("field.target_user"), e.GetAssignee().GetLogin())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "review_requested":
-		a.InsertField("アクション", "PR のレビューをお願いされました", true)
-		a.InsertField("対象者", e.GetRequestedReviewer().GetLogin())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.review_requested"), true)
+		a.InsertField(l.T("field.target_user"), e.GetRequestedReviewer().GetLogin())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "review_request_removed":
-		a.InsertField("アクション", "PR のレビュー要求が取下げされました", true)
-		a.InsertField("対象者", e.GetRequestedReviewer().GetLogin())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.review_request_removed"), true)
+		a.InsertField(l.T("field.target_user"), e.GetRequestedReviewer().GetLogin())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "ready_for_review":
-		a.InsertField("アクション", "PR の準備が整いました", true)
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.ready_for_review"), true)
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		a.InsertField(l.T("field.author"), e.GetPullRequest().GetUser().GetLogin(), true)
+		a.InsertField(l.T("field.reviewers"), requestedReviewers(e.GetPullRequest()), true)
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
+	case "converted_to_draft":
+		a.InsertField(l.T("field.action"), l.T("pull_request.converted_to_draft"), true)
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "labeled":
-		a.InsertField("アクション", "PR にラベルが付与されました", true)
-		a.InsertField("ラベル", e.GetLabel().GetName())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.labeled"), true)
+		a.InsertField(l.T("field.label"), e.GetLabel().GetName())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "unlabeled":
-		a.InsertField("アクション", "PR のラベルが外されました", true)
-		a.InsertField("ラベル", e.GetLabel().GetName())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.unlabeled"), true)
+		a.InsertField(l.T("field.label"), e.GetLabel().GetName())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "locked":
-		a.InsertField("アクション", "PR がロックされました", true)
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.locked"), true)
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "unlocked":
-		a.InsertField("アクション", "PR のロックが解除されました", true)
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.unlocked"), true)
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "synchronize":
-		a.InsertField("アクション", "PullRequestEvent synchronize")
+		a.InsertField(l.T("field.action"), l.T("pull_request.synchronize"), true)
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		gm.insertSynchronizedCommits(l, a, e.GetRepo(), e.GetBefore(), e.GetAfter())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	default:
-		a.InsertField("アクション", fmt.Sprintf("PullRequestEvent (%s)", e.GetAction()))
+		a.InsertField(l.T("field.action"), fmt.Sprintf("PullRequestEvent (%s)", e.GetAction()))
 	}
+	gm.muteDraft(l, a, e.GetPullRequest(), e.GetAction())
 	return a.Build()
 }
 
-func buildPullRequestReviewEvent(e *pullRequestReviewEvent) (*bytes.Buffer, error) {
+// converted_to_draft 以外の Draft PR の Event は DraftSuppress の場合は通知しない
+func (gm *GitHubMessage) suppressDraft(pr *github.PullRequest, action string) bool {
+	return gm.draftMode == DraftSuppress && pr.GetDraft() && action != "converted_to_draft"
+}
+
+func (gm *GitHubMessage) muteDraft(l *i18n.Localizer, a editableAttachment, pr *github.PullRequest, action string) {
+	if gm.draftMode == DraftShow || !pr.GetDraft() || action == "converted_to_draft" {
+		return
+	}
+	a.SetColor(builder.DraftColor)
+	a.InsertField(l.T("field.state"), l.T("state.draft"), true)
+}
+
+func requestedReviewers(pr *github.PullRequest) string {
+	var reviewers []string
+	for _, u := range pr.RequestedReviewers {
+		reviewers = append(reviewers, u.GetLogin())
+	}
+	for _, t := range pr.RequestedTeams {
+		reviewers = append(reviewers, t.GetName())
+	}
+	return strings.Join(reviewers, ", ")
+}
+
+// before, after の比較リンクと、API が利用可能であれば追加されたコミットを挿入する
+func (gm *GitHubMessage) insertSynchronizedCommits(l *i18n.Localizer, a editableAttachment, repo *github.Repository, before, after string) {
+	a.InsertField(
+		l.T("field.changes"),
+		fmt.Sprintf("<%s|%s...%s>", compareHTMLURL(repo, before, after), shortSHA(before), shortSHA(after)),
+	)
+	if gm.api == nil || before == "" || after == "" {
+		return
+	}
+	commits, err := gm.api.CompareCommits(repo.GetOwner().GetLogin(), repo.GetName(), before, after)
+	if err != nil {
+		fmt.Printf("Compare Failed: %v\n", err)
+		return
+	}
+	var b strings.Builder
+	for _, c := range commits {
+		b.WriteString(commitLine(c.GetHTMLURL(), c.GetSHA(), c.GetCommit().GetMessage()))
+	}
+	a.InsertField(l.T("field.commit"), b.String())
+}
+
+func compareHTMLURL(repo *github.Repository, before, after string) string {
+	return fmt.Sprintf("%s/compare/%s...%s", repo.GetHTMLURL(), before, after)
+}
+
+func commitLink(repoURL, sha string) string {
+	return fmt.Sprintf("<%s/commit/%s|%s>", repoURL, sha, shortSHA(sha))
+}
+
+func commitLine(url, sha, message string) string {
+	return fmt.Sprintf("<%s|%s> %s\n", url, shortSHA(sha), message)
+}
+
+func shortSHA(sha string) string {
+	if len(sha) < 7 {
+		return sha
+	}
+	return sha[:7]
+}
+
+func (gm *GitHubMessage) buildPullRequestReviewEvent(l *i18n.Localizer, e *pullRequestReviewEvent) (*bytes.Buffer, error) {
 	a := builder.NewAttachment()
-	a.InsertField("アカウント", e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
 	switch e.GetAction() {
 	case "submitted":
-		a.InsertField("アクション", "PR のレビューがされました", true)
-		a.InsertField("タイトル", e.GetPullRequest().GetTitle())
-		a.InsertField("内容", e.GetReview().GetBody())
-		a.InsertField("リンク", e.GetReview().GetHTMLURL())
+		switch e.GetReview().GetState() {
+		case "approved":
+			a.SetColor(builder.ApprovedColor)
+			a.InsertField(l.T("field.action"), l.T("pull_request_review.approved"), true)
+		case "changes_requested":
+			a.SetColor(builder.ChangesRequestedColor)
+			a.InsertField(l.T("field.action"), l.T("pull_request_review.changes_requested"), true)
+		case "commented":
+			a.SetColor(builder.CommentedColor)
+			a.InsertField(l.T("field.action"), l.T("pull_request_review.commented"), true)
+		default:
+			a.InsertField(l.T("field.action"), l.T("pull_request_review.submitted"), true)
+		}
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		a.InsertField(l.T("field.body"), gm.markdown(e.GetReview().GetBody()))
+		a.InsertField(l.T("field.inline_comments"), gm.countReviewComments(l, e), true)
+		a.InsertField(l.T("field.link"), e.GetReview().GetHTMLURL())
+	case "dismissed":
+		a.SetColor(builder.DismissedColor)
+		a.InsertField(l.T("field.action"), l.T("pull_request_review.dismissed"), true)
+		a.InsertField(l.T("field.reviewers"), e.GetReview().GetUser().GetLogin(), true)
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		a.InsertField(l.T("field.reason"), gm.getDismissalMessage(e))
+		a.InsertField(l.T("field.link"), e.GetReview().GetHTMLURL())
 	default:
-		a.InsertField("アクション", fmt.Sprintf("PullRequestReviewEvent (%s)", e.GetAction()))
+		a.InsertField(l.T("field.action"), fmt.Sprintf("PullRequestReviewEvent (%s)", e.GetAction()))
 	}
 	return a.Build()
 }
 
-func buildPullRequestReviewCommentEvent(e *pullRequestReviewCommentEvent) (*bytes.Buffer, error) {
+// API が利用できない場合は空文字を返す (フィールドは挿入されない)
+func (gm *GitHubMessage) countReviewComments(l *i18n.Localizer, e *pullRequestReviewEvent) string {
+	if gm.api == nil {
+		return ""
+	}
+	count, err := gm.api.CountReviewComments(
+		e.GetRepo().GetOwner().GetLogin(), e.GetRepo().GetName(),
+		e.GetPullRequest().GetNumber(), e.GetReview().GetID(),
+	)
+	if err != nil {
+		fmt.Printf("Count Review Comments Failed: %v\n", err)
+		return ""
+	}
+	return l.T("format.count", count)
+}
+
+func (gm *GitHubMessage) getDismissalMessage(e *pullRequestReviewEvent) string {
+	if gm.api == nil {
+		return ""
+	}
+	message, err := gm.api.GetDismissalMessage(
+		e.GetRepo().GetOwner().GetLogin(), e.GetRepo().GetName(),
+		e.GetPullRequest().GetNumber(), e.GetReview().GetID(),
+	)
+	if err != nil {
+		fmt.Printf("Get Dismissal Message Failed: %v\n", err)
+		return ""
+	}
+	return message
+}
+
+func (gm *GitHubMessage) buildPullRequestReviewCommentEvent(l *i18n.Localizer, e *pullRequestReviewCommentEvent) (*bytes.Buffer, error) {
 	a := builder.NewAttachment()
-	a.InsertField("アカウント", e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
 	switch e.GetAction() {
 	case "created":
-		a.InsertField("アクション", "PR にコメントされました", true)
-		a.InsertField("コメント", e.GetComment().GetBody())
-		a.InsertField("リンク", e.GetComment().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request_review_comment.created"), true)
+		a.InsertField(l.T("field.comment"), gm.markdown(e.GetComment().GetBody()))
+		a.InsertField(l.T("field.link"), e.GetComment().GetHTMLURL())
 	case "edited":
-		a.InsertField("アクション", "PR のコメントが変更されました", true)
+		a.InsertField(l.T("field.action"), l.T("pull_request_review_comment.edited"), true)
 		if body := e.GetChanges().GetBody(); body != nil {
-			a.InsertField("コメント(変更前)", e.GetChanges().GetBody().GetFrom())
-			a.InsertField("コメント(変更後)", e.GetComment().GetBody())
+			a.InsertField(l.T("field.comment_from"), gm.markdown(e.GetChanges().GetBody().GetFrom()))
+			a.InsertField(l.T("field.comment_to"), gm.markdown(e.GetComment().GetBody()))
 		}
-		a.InsertField("リンク", e.GetComment().GetHTMLURL())
+		a.InsertField(l.T("field.link"), e.GetComment().GetHTMLURL())
 	case "deleted":
-		a.InsertField("アクション", "PR のコメントが削除されました", true)
-		a.InsertField("コメント", e.GetComment().GetBody())
-		a.InsertField("リンク", e.GetComment().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request_review_comment.deleted"), true)
+		a.InsertField(l.T("field.comment"), gm.markdown(e.GetComment().GetBody()))
+		a.InsertField(l.T("field.link"), e.GetComment().GetHTMLURL())
 	default:
-		a.InsertField("アクション", fmt.Sprintf("PullRequestReviewCommentEvent (%s)", e.GetAction()))
+		a.InsertField(l.T("field.action"), fmt.Sprintf("PullRequestReviewCommentEvent (%s)", e.GetAction()))
 	}
 	return a.Build()
 }
 
-func buildPullRequestTargetEvent(e *pullRequestTargetEvent) (*bytes.Buffer, error) {
+func (gm *GitHubMessage) buildPullRequestTargetEvent(l *i18n.Localizer, e *pullRequestTargetEvent) (*bytes.Buffer, error) {
+	if gm.suppressDraft(e.GetPullRequest(), e.GetAction()) {
+		return gm.suppressed()
+	}
 	a := builder.NewAttachment()
-	a.InsertField("アカウント", e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
 	switch e.GetAction() {
 	case "opened":
-		a.InsertField("アクション", "PR がオープンされました", true)
-		a.InsertField("タイトル", e.GetPullRequest().GetTitle())
-		a.InsertField("内容", e.GetPullRequest().GetBody())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.opened"), true)
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		a.InsertField(l.T("field.body"), gm.markdown(e.GetPullRequest().GetBody()))
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "edited":
-		a.InsertField("アクション", "PR が編集されました", true)
+		a.InsertField(l.T("field.action"), l.T("pull_request.edited"), true)
 		if title := e.GetChanges().GetTitle(); title != nil {
-			a.InsertField("タイトル(変更前)", title.GetFrom())
-			a.InsertField("タイトル(変更後)", e.GetPullRequest().GetTitle())
+			a.InsertField(l.T("field.title_from"), title.GetFrom())
+			a.InsertField(l.T("field.title_to"), e.GetPullRequest().GetTitle())
 		}
 		if body := e.GetChanges().GetBody(); body != nil {
-			a.InsertField("内容(変更前)", body.GetFrom())
-			a.InsertField("内容(変更後)", e.GetPullRequest().GetBody())
+			a.InsertField(l.T("field.body_from"), gm.markdown(body.GetFrom()))
+			a.InsertField(l.T("field.body_to"), gm.markdown(e.GetPullRequest().GetBody()))
 		}
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "closed":
-		a.InsertField("アクション", "PR がクローズされました", true)
-		a.InsertField("タイトル", e.GetPullRequest().GetTitle())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.closed"), true)
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "reopened":
-		a.InsertField("アクション", "PR が再オープンされました", true)
-		a.InsertField("タイトル", e.GetPullRequest().GetTitle())
-		a.InsertField("内容", e.GetPullRequest().GetBody())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.reopened"), true)
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		a.InsertField(l.T("field.body"), gm.markdown(e.GetPullRequest().GetBody()))
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "assigned":
-		a.InsertField("アクション", "PR にアサインされました", true)
-		a.InsertField("対象者", e.GetAssignee().GetLogin())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.assigned"), true)
+		a.InsertField(l.T("field.target_user"), e.GetAssignee().GetLogin())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "unassigned":
-		a.InsertField("アクション", "PR にアンアサインされました", true)
-		a.InsertField("対象者", e.GetAssignee().GetLogin())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.unassigned"), true)
+		a.InsertField(l.T("field.target_user"), e.GetAssignee().GetLogin())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "review_requested":
-		a.InsertField("アクション", "PR のレビューをお願いされました", true)
-		a.InsertField("対象者", e.GetRequestedReviewer().GetLogin())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.review_requested"), true)
+		a.InsertField(l.T("field.target_user"), e.GetRequestedReviewer().GetLogin())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "review_request_removed":
-		a.InsertField("アクション", "PR のレビュー要求が取下げされました", true)
-		a.InsertField("対象者", e.GetRequestedReviewer().GetLogin())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.review_request_removed"), true)
+		a.InsertField(l.T("field.target_user"), e.GetRequestedReviewer().GetLogin())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "ready_for_review":
-		a.InsertField("アクション", "PR の準備が整いました", true)
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.ready_for_review"), true)
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		a.InsertField(l.T("field.author"), e.GetPullRequest().GetUser().GetLogin(), true)
+		a.InsertField(l.T("field.reviewers"), requestedReviewers(e.GetPullRequest()), true)
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
+	case "converted_to_draft":
+		a.InsertField(l.T("field.action"), l.T("pull_request.converted_to_draft"), true)
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "labeled":
-		a.InsertField("アクション", "PR にラベルが付与されました", true)
-		a.InsertField("ラベル", e.GetLabel().GetName())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.labeled"), true)
+		a.InsertField(l.T("field.label"), e.GetLabel().GetName())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "unlabeled":
-		a.InsertField("アクション", "PR のラベルが外されました", true)
-		a.InsertField("ラベル", e.GetLabel().GetName())
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.unlabeled"), true)
+		a.InsertField(l.T("field.label"), e.GetLabel().GetName())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "locked":
-		a.InsertField("アクション", "PR がロックされました", true)
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.locked"), true)
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "unlocked":
-		a.InsertField("アクション", "PR のロックが解除されました", true)
-		a.InsertField("リンク", e.GetPullRequest().GetHTMLURL())
+		a.InsertField(l.T("field.action"), l.T("pull_request.unlocked"), true)
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	case "synchronize":
-		a.InsertField("アクション", "PullRequestEvent synchronize")
+		a.InsertField(l.T("field.action"), l.T("pull_request.synchronize"), true)
+		a.InsertField(l.T("field.title"), e.GetPullRequest().GetTitle())
+		gm.insertSynchronizedCommits(l, a, e.GetRepo(), e.GetBefore(), e.GetAfter())
+		a.InsertField(l.T("field.link"), e.GetPullRequest().GetHTMLURL())
 	default:
-		a.InsertField("アクション", fmt.Sprintf("PullRequestTargetEvent (%s)", e.GetAction()))
+		a.InsertField(l.T("field.action"), fmt.Sprintf("PullRequestTargetEvent (%s)", e.GetAction()))
 	}
+	gm.muteDraft(l, a, e.GetPullRequest(), e.GetAction())
 	return a.Build()
 }
 
-func buildPushEvent(e *pushEvent) (*bytes.Buffer, error) {
+func (gm *GitHubMessage) buildPushEvent(l *i18n.Localizer, e *pushEvent) (*bytes.Buffer, error) {
 	a := builder.NewAttachment()
-	a.InsertField("アカウント", e.GetSender().GetLogin(), true)
-	a.InsertField("アクション", "プッシュされました", true)
-	a.InsertField("対象", e.GetRef())
-	if e.Commits != nil {
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	if e.GetDeleted() {
+		if strings.HasPrefix(e.GetRef(), "refs/tags/") {
+			a.InsertField(l.T("field.action"), l.T("delete.tag"), true)
+		} else {
+			a.InsertField(l.T("field.action"), l.T("delete.branch"), true)
+		}
+		a.InsertField(l.T("field.target"), e.GetRef())
+		a.InsertField(l.T("field.before_deletion"), commitLink(e.GetRepo().GetHTMLURL(), e.GetBefore()))
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+		return a.Build()
+	}
+
+	if e.GetForced() {
+		a.SetColor(builder.ForcePushColor)
+		a.InsertField(l.T("field.action"), l.T("push.forced"), true)
+	} else {
+		a.InsertField(l.T("field.action"), l.T("push"), true)
+	}
+	a.InsertField(l.T("field.target"), e.GetRef())
+	if e.GetCreated() {
+		a.InsertField(l.T("field.changes"), fmt.Sprintf("<%s|%s>", e.GetCompare(), shortSHA(e.GetAfter())))
+	} else {
+		a.InsertField(l.T("field.changes"), fmt.Sprintf("<%s|%s...%s>", e.GetCompare(), shortSHA(e.GetBefore()), shortSHA(e.GetAfter())))
+	}
+
+	if len(e.Commits) > 0 {
+		limit := gm.pushCommitLimit
+		if limit <= 0 {
+			limit = DefaultPushCommitLimit
+		}
 		var b strings.Builder
-		for _, c := range e.Commits {
-			b.WriteString(
-				fmt.Sprintf("<%s|%s> %s\n", c.GetURL(), c.GetID()[:7], c.GetMessage()),
-			)
+		for i, c := range e.Commits {
+			if i == limit {
+				// コミット一覧を切り詰めた場合は比較ページへ案内する
+				a.SetLink(e.GetCompare())
+				b.WriteString(l.T("push.more_commits", e.GetCompare(), len(e.Commits)-limit))
+				break
+			}
+			b.WriteString(gm.pushCommitLine(c))
 		}
-		a.InsertField("Commit", b.String())
+		a.InsertField(l.T("field.commit"), b.String())
+		a.InsertField(l.T("field.author"), gm.pushAuthorSummary(e.Commits))
+	} else if c := e.GetHeadCommit(); c != nil {
+		a.InsertField(l.T("field.commit"), gm.pushCommitLine(c))
 	}
-	a.InsertField("リンク", e.GetRepo().GetHTMLURL())
+	a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
 	return a.Build()
 }
+
+func buildRepositoryEvent(l *i18n.Localizer, e *repositoryEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	switch e.GetAction() {
+	case "created":
+		a.InsertField(l.T("field.action"), l.T("repository.created"), true)
+		a.InsertField(l.T("field.repository"), e.GetRepo().GetFullName())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	case "deleted":
+		a.InsertField(l.T("field.action"), l.T("repository.deleted"), true)
+		a.InsertField(l.T("field.repository"), e.GetRepo().GetFullName())
+	case "archived":
+		a.InsertField(l.T("field.action"), l.T("repository.archived"), true)
+		a.InsertField(l.T("field.repository"), e.GetRepo().GetFullName())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	case "unarchived":
+		a.InsertField(l.T("field.action"), l.T("repository.unarchived"), true)
+		a.InsertField(l.T("field.repository"), e.GetRepo().GetFullName())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	case "publicized":
+		a.InsertField(l.T("field.action"), l.T("repository.publicized"), true)
+		a.InsertField(l.T("field.repository"), e.GetRepo().GetFullName())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	case "privatized":
+		a.InsertField(l.T("field.action"), l.T("repository.privatized"), true)
+		a.InsertField(l.T("field.repository"), e.GetRepo().GetFullName())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	case "renamed":
+		a.InsertField(l.T("field.action"), l.T("repository.renamed"), true)
+		a.InsertField(l.T("field.repository_name_from"), e.GetNameFrom())
+		a.InsertField(l.T("field.repository_name_to"), e.GetRepo().GetName())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	case "transferred":
+		a.InsertField(l.T("field.action"), l.T("repository.transferred"), true)
+		a.InsertField(l.T("field.owner_from"), e.GetOwnerFrom())
+		a.InsertField(l.T("field.owner_to"), e.GetRepo().GetOwner().GetLogin())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	case "edited":
+		a.InsertField(l.T("field.action"), l.T("repository.edited"), true)
+		a.InsertField(l.T("field.repository"), e.GetRepo().GetFullName())
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("RepositoryEvent (%s)", e.GetAction()))
+	}
+	return a.Build()
+}
+
+func buildRepositoryRulesetEvent(l *i18n.Localizer, e *repositoryRulesetEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	switch e.GetAction() {
+	case "created":
+		a.InsertField(l.T("field.action"), l.T("repository_ruleset.created"), true)
+	case "edited":
+		a.InsertField(l.T("field.action"), l.T("repository_ruleset.edited"), true)
+	case "deleted":
+		a.InsertField(l.T("field.action"), l.T("repository_ruleset.deleted"), true)
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("RepositoryRulesetEvent (%s)", e.GetAction()))
+		return a.Build()
+	}
+	r := e.GetRuleset()
+	a.InsertField(l.T("field.ruleset"), r.GetName())
+	a.InsertField(l.T("field.target"), r.GetTarget(), true)
+	a.InsertField(l.T("field.enforcement"), r.GetEnforcement(), true)
+	a.InsertField(l.T("field.rules"), strings.Join(r.GetRuleTypes(), ", "))
+	if e.GetAction() != "deleted" {
+		a.InsertField(l.T("field.link"), rulesetHTMLURL(e, r))
+	}
+	return a.Build()
+}
+
+func rulesetHTMLURL(e *repositoryRulesetEvent, r *repositoryRuleset) string {
+	if e.GetRepo() != nil {
+		return fmt.Sprintf("%s/rules/%d", e.GetRepo().GetHTMLURL(), r.GetID())
+	}
+	return fmt.Sprintf("https://github.com/organizations/%s/settings/rules/%d", e.GetOrg().GetLogin(), r.GetID())
+}
+
+func buildSecurityAlertEvent(l *i18n.Localizer, eventType string, e *securityAlertEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	if !resolvedAlertActions[e.GetAction()] {
+		a.SetColor(builder.ErrorColor)
+	}
+	a.InsertField(l.T("field.action"), fmt.Sprintf("%s (%s)", l.T("security_alert."+eventType), e.GetAction()), true)
+	if summary := e.GetSummary(); summary != "" {
+		a.InsertField(l.T("field.alert"), summary)
+	}
+	if severity := e.GetSeverity(); severity != "" {
+		a.InsertField(l.T("field.severity"), severity, true)
+	}
+	if pkg := e.GetPackage(); pkg != "" {
+		a.InsertField(l.T("field.package"), pkg, true)
+	}
+	if ref := firstString(e.Ref); ref != "" {
+		a.InsertField(l.T("field.ref"), ref, true)
+	}
+	a.InsertField(l.T("field.link"), e.GetHTMLURL())
+	return a.Build()
+}
+
+func buildSponsorshipEvent(l *i18n.Localizer, e *sponsorshipEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	s := e.GetSponsorship()
+	a.InsertField(l.T("field.sponsor"), s.GetSponsor().GetLogin(), true)
+	switch e.GetAction() {
+	case "created":
+		a.SetColor(builder.CelebrationColor)
+		a.InsertField(l.T("field.action"), l.T("sponsorship.created"), true)
+	case "tier_changed":
+		a.InsertField(l.T("field.action"), l.T("sponsorship.tier_changed"), true)
+	case "edited":
+		a.InsertField(l.T("field.action"), l.T("sponsorship.edited"), true)
+	case "pending_tier_change":
+		a.InsertField(l.T("field.action"), l.T("sponsorship.pending_tier_change"), true)
+	case "pending_cancellation":
+		a.SetColor(builder.WarningColor)
+		a.InsertField(l.T("field.action"), l.T("sponsorship.pending_cancellation"), true)
+	case "cancelled":
+		a.SetColor(builder.WarningColor)
+		a.InsertField(l.T("field.action"), l.T("sponsorship.cancelled"), true)
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("SponsorshipEvent (%s)", e.GetAction()))
+		return a.Build()
+	}
+	a.InsertField(l.T("field.target"), s.GetSponsorable().GetLogin(), true)
+	a.InsertField(l.T("field.tier"), sponsorshipTierSummary(l, s.GetTier()), true)
+	if from := e.GetTierFrom(); from != nil {
+		a.InsertField(l.T("field.before"), sponsorshipTierSummary(l, from), true)
+	}
+	if from := e.GetPrivacyLevelFrom(); from != "" {
+		a.InsertField(l.T("field.privacy"), l.T("format.change", from, s.GetPrivacyLevel()), true)
+	}
+	if date := e.GetEffectiveDate(); date != "" {
+		a.InsertField(l.T("field.effective_date"), formatDate(l, date), true)
+	}
+	return a.Build()
+}
+
+// ティア名 ($金額 / 月)
+func sponsorshipTierSummary(l *i18n.Localizer, t *sponsorshipTier) string {
+	price := l.T("format.per_month", formatCents(t.GetMonthlyPriceInCents()))
+	if t.GetIsOneTime() {
+		price = l.T("format.one_time", formatCents(t.GetMonthlyPriceInCents()))
+	}
+	if t.GetName() == "" {
+		return price
+	}
+	return fmt.Sprintf("%s (%s)", t.GetName(), price)
+}
+
+func (gm *GitHubMessage) buildStarEvent(l *i18n.Localizer, e *starEvent) (*bytes.Buffer, error) {
+	switch e.GetAction() {
+	case "created":
+		return gm.buildStarred(l, e.GetSender(), e.GetRepo())
+	case "deleted":
+		a := builder.NewAttachment()
+		a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+		a.InsertField(l.T("field.action"), l.T("star.deleted"), true)
+		a.InsertField(l.T("field.repository"), e.GetRepo().GetFullName())
+		a.InsertField(l.T("field.stars"), strconv.Itoa(e.GetRepo().GetStargazersCount()), true)
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+		return a.Build()
+	}
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.action"), fmt.Sprintf("StarEvent (%s)", e.GetAction()))
+	return a.Build()
+}
+
+// スターを付けると star (created) と watch (started) の両方が送信される
+// (両方が有効な場合は buildStarred で後から届いた方を数えない)
+func (gm *GitHubMessage) buildWatchEvent(l *i18n.Localizer, e *watchEvent) (*bytes.Buffer, error) {
+	if e.GetAction() == "started" {
+		return gm.buildStarred(l, e.GetSender(), e.GetRepo())
+	}
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.action"), fmt.Sprintf("WatchEvent (%s)", e.GetAction()))
+	return a.Build()
+}
+
+func (gm *GitHubMessage) buildStarred(l *i18n.Localizer, sender *github.User, repo *github.Repository) (*bytes.Buffer, error) {
+	paired, err := gm.pairedStar(sender, repo)
+	if err != nil {
+		return nil, err
+	}
+	if paired {
+		return gm.suppressed()
+	}
+	count := repo.GetStargazersCount()
+	milestone, err := gm.reachStarMilestone(repo)
+	if err != nil {
+		return nil, err
+	}
+	if milestone > 0 {
+		a := builder.NewAttachment()
+		a.SetColor(builder.CelebrationColor)
+		a.InsertField(l.T("field.action"), l.T("star.milestone", milestone))
+		a.InsertField(l.T("field.repository"), repo.GetFullName())
+		a.InsertField(l.T("field.link"), repo.GetHTMLURL())
+		return a.Build()
+	}
+
+	if gm.starBatchSize > 1 && gm.store != nil {
+		now := time.Now()
+		day, rest, err := gm.starBatchRest(repo, now)
+		if err != nil {
+			return nil, err
+		}
+		n, err := gm.store.Incr(starBatchKey(repo, now))
+		if err != nil {
+			return nil, err
+		}
+		action := l.T("star.batch", n)
+		if rest > 0 {
+			action = l.T("star.batch_rest", day, rest)
+		} else if n%gm.starBatchSize != 0 {
+			return nil, nil
+		}
+		a := builder.NewAttachment()
+		a.InsertField(l.T("field.action"), action, true)
+		a.InsertField(l.T("field.stars"), strconv.Itoa(count), true)
+		a.InsertField(l.T("field.repository"), repo.GetFullName())
+		a.InsertField(l.T("field.link"), repo.GetHTMLURL())
+		return a.Build()
+	}
+
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), sender.GetLogin(), true)
+	a.InsertField(l.T("field.action"), l.T("star.created"), true)
+	a.InsertField(l.T("field.repository"), repo.GetFullName())
+	a.InsertField(l.T("field.stars"), strconv.Itoa(count), true)
+	a.InsertField(l.T("field.link"), repo.GetHTMLURL())
+	return a.Build()
+}
+
+// 同じアカウントの star・watch のうち他方が starPairWindow 以内に届いている場合は true を返す
+// (同じ種類の Event は再送や付け直しのため、続けて届いても数える)
+func (gm *GitHubMessage) pairedStar(sender *github.User, repo *github.Repository) (bool, error) {
+	if gm.store == nil || sender.GetLogin() == "" {
+		return false, nil
+	}
+	key := fmt.Sprintf("star_sender/%s/%s", repo.GetFullName(), sender.GetLogin())
+	value, err := gm.store.Get(key)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	// <Event> <日時>
+	if v := strings.SplitN(string(value), " ", 2); len(v) == 2 && v[0] != gm.eventType {
+		if t, err := time.Parse(time.RFC3339, v[1]); err == nil && now.Sub(t) < starPairWindow {
+			// 次の Event は新しいスターとして数える
+			return true, gm.store.Set(key, []byte{})
+		}
+	}
+	return false, gm.store.Set(key, []byte(gm.eventType+" "+now.Format(time.RFC3339)))
+}
+
+// 超えた閾値のうち、まだ通知していない最大のものを返す (ない場合は 0)
+// store がない場合は重複を判定できないため、閾値ちょうどの場合のみ
+// 通知した閾値より小さい閾値も通知済みとして記録する (後から通知しないため)
+func (gm *GitHubMessage) reachStarMilestone(repo *github.Repository) (int, error) {
+	count := repo.GetStargazersCount()
+	if gm.store == nil {
+		for _, m := range gm.starMilestones {
+			if m == count {
+				return m, nil
+			}
+		}
+		return 0, nil
+	}
+	var reached []int
+	for _, m := range gm.starMilestones {
+		if m <= count {
+			reached = append(reached, m)
+		}
+	}
+	if len(reached) == 0 {
+		return 0, nil
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(reached)))
+	celebrated, err := gm.store.Get(starMilestoneKey(repo, reached[0]))
+	if err != nil || celebrated != nil {
+		return 0, err
+	}
+	for _, m := range reached {
+		if err := gm.store.Set(starMilestoneKey(repo, m), []byte(time.Now().Format(time.RFC3339))); err != nil {
+			return 0, err
+		}
+	}
+	return reached[0], nil
+}
+
+func starMilestoneKey(repo *github.Repository, milestone int) string {
+	return fmt.Sprintf("star_milestone/%s/%d", repo.GetFullName(), milestone)
+}
+
+// 日付が変わった最初のスターの場合、前回の日付と N 件に満たず通知していないスターの件数を返す
+func (gm *GitHubMessage) starBatchRest(repo *github.Repository, now time.Time) (string, int, error) {
+	key := fmt.Sprintf("star_batch_day/%s", repo.GetFullName())
+	last, err := gm.store.Get(key)
+	if err != nil {
+		return "", 0, err
+	}
+	today := now.Format(starBatchDayLayout)
+	if string(last) == today {
+		return "", 0, nil
+	}
+	if err := gm.store.Set(key, []byte(today)); err != nil {
+		return "", 0, err
+	}
+	day, err := time.Parse(starBatchDayLayout, string(last))
+	if err != nil {
+		return "", 0, nil
+	}
+	value, err := gm.store.Get(starBatchKey(repo, day))
+	if err != nil || value == nil {
+		return "", 0, err
+	}
+	n, err := strconv.Atoi(string(value))
+	if err != nil {
+		return "", 0, err
+	}
+	return string(last), n % gm.starBatchSize, nil
+}
+
+func starBatchKey(repo *github.Repository, now time.Time) string {
+	return fmt.Sprintf("star_batch/%s/%s", repo.GetFullName(), now.Format(starBatchDayLayout))
+}
+
+func buildTeamAddEvent(l *i18n.Localizer, e *teamAddEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	a.InsertField(l.T("field.action"), l.T("team_add"), true)
+	a.InsertField(l.T("field.team"), e.GetTeam().GetName(), true)
+	a.InsertField(l.T("field.repository"), e.GetRepo().GetFullName(), true)
+	a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	return a.Build()
+}
+
+func buildTeamEvent(l *i18n.Localizer, e *teamEvent) (*bytes.Buffer, error) {
+	a := builder.NewAttachment()
+	a.InsertField(l.T("field.account"), e.GetSender().GetLogin(), true)
+	switch e.GetAction() {
+	case "created":
+		a.InsertField(l.T("field.action"), l.T("team.created"), true)
+		a.InsertField(l.T("field.team"), e.GetTeam().GetName())
+		a.InsertField(l.T("field.privacy"), e.GetTeam().GetPrivacy(), true)
+		a.InsertField(l.T("field.link"), teamHTMLURL(e.GetOrg(), e.GetTeam()))
+	case "deleted":
+		a.InsertField(l.T("field.action"), l.T("team.deleted"), true)
+		a.InsertField(l.T("field.team"), e.GetTeam().GetName())
+	case "edited":
+		a.InsertField(l.T("field.action"), l.T("team.edited"), true)
+		if name := e.GetChanges().GetName(); name != nil {
+			a.InsertField(l.T("field.team_name_from"), name.GetFrom())
+			a.InsertField(l.T("field.team_name_to"), e.GetTeam().GetName())
+		} else {
+			a.InsertField(l.T("field.team"), e.GetTeam().GetName())
+		}
+		if privacy := e.GetChanges().GetPrivacy(); privacy != nil {
+			a.InsertField(l.T("field.privacy_from"), privacy.GetFrom(), true)
+			a.InsertField(l.T("field.privacy_to"), e.GetTeam().GetPrivacy(), true)
+		}
+		if from := e.GetChanges().GetRepository().GetPermissions().GetFrom(); from != nil {
+			a.InsertField(l.T("field.repository"), e.GetRepo().GetFullName())
+			a.InsertField(l.T("field.permission_from"), teamPermissionsFrom(from), true)
+		}
+		a.InsertField(l.T("field.link"), teamHTMLURL(e.GetOrg(), e.GetTeam()))
+	case "added_to_repository":
+		a.InsertField(l.T("field.action"), l.T("team_add"), true)
+		a.InsertField(l.T("field.team"), e.GetTeam().GetName(), true)
+		a.InsertField(l.T("field.repository"), e.GetRepo().GetFullName(), true)
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	case "removed_from_repository":
+		a.InsertField(l.T("field.action"), l.T("team.removed_from_repository"), true)
+		a.InsertField(l.T("field.team"), e.GetTeam().GetName(), true)
+		a.InsertField(l.T("field.repository"), e.GetRepo().GetFullName(), true)
+		a.InsertField(l.T("field.link"), e.GetRepo().GetHTMLURL())
+	default:
+		a.InsertField(l.T("field.action"), fmt.Sprintf("TeamEvent (%s)", e.GetAction()))
+	}
+	return a.Build()
+}
+
+func teamHTMLURL(org *github.Organization, team *github.Team) string {
+	if org.GetLogin() == "" || team.GetSlug() == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://github.com/orgs/%s/teams/%s", org.GetLogin(), team.GetSlug())
+}
+
+func teamPermissionsFrom(p *github.TeamPermissionsFrom) string {
+	switch {
+	case p.GetAdmin():
+		return "admin"
+	case p.GetPush():
+		return "push"
+	case p.GetPull():
+		return "pull"
+	}
+	return "none"
+}
+
+func toP(s string) *string {
+	return &s
+}
@@ -4,25 +4,27 @@ import (
 	"bytes"
 	"fmt"
 	"errors"
-	"os"
-)
 
-const (
-	TypeEnv = "INCOME_TYPE"
-	GitHubType = "github"
+	"github.com/SongCastle/ggnb/config"
+	"github.com/SongCastle/ggnb/income/builder"
 )
 
 type AbstractMessage interface {
 	Init(headers, body interface{}) error
 	ToPayload() (*bytes.Buffer, error)
+	Route() string
+	// 通知を控える時間帯でも通知する場合は true
+	Urgent() bool
+	// ダイジェストに記録する Event の概要
+	Digest() *builder.DigestEntry
 	ToDummyPayload() (*bytes.Buffer, error)
 }
 
-func NewMessage() (AbstractMessage, error) {
+func NewMessage(cfg *config.Config) (AbstractMessage, error) {
 	// Only GitHub
-	switch os.Getenv(TypeEnv) {
-	case GitHubType:
-		return &GitHubMessage{}, nil
+	switch cfg.Income.Type {
+	case config.GitHubType:
+		return newGitHubMessage(cfg)
 	}
-	return nil, errors.New(fmt.Sprintf("Invalid %s", TypeEnv))
+	return nil, errors.New(fmt.Sprintf("Invalid %s", config.IncomeTypeEnv))
 }
@@ -3,6 +3,7 @@ package message
 import (
 	"bytes"
 
+	"github.com/SongCastle/ggnb/income/builder"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -20,6 +21,22 @@ func (m *MockedMessage) ToPayload() (*bytes.Buffer, error) {
 	return args[0].(*bytes.Buffer), args.Error(1)
 }
 
+func (m *MockedMessage) Route() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockedMessage) Urgent() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func (m *MockedMessage) Digest() *builder.DigestEntry {
+	args := m.Called()
+	e, _ := args[0].(*builder.DigestEntry)
+	return e
+}
+
 func (m *MockedMessage) ToDummyPayload() (*bytes.Buffer, error) {
 	args := m.Called()
 	return args[0].(*bytes.Buffer), args.Error(1)
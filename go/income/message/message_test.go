@@ -2,39 +2,78 @@ package message
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 	"unsafe"
 
+	"github.com/SongCastle/ggnb/config"
+	"github.com/SongCastle/ggnb/income/api"
 	"github.com/SongCastle/ggnb/income/builder"
+	"github.com/SongCastle/ggnb/income/filter"
+	"github.com/SongCastle/ggnb/income/i18n"
+	"github.com/SongCastle/ggnb/income/markdown"
+	"github.com/SongCastle/ggnb/income/template"
+	"github.com/SongCastle/ggnb/store"
+	"github.com/google/go-github/v38/github"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestNewMessage(t *testing.T) {
 	assert := assert.New(t)
-	beforeType := os.Getenv(TypeEnv)
 
 	t.Run("without type", func(t *testing.T) {
-		if err := os.Unsetenv(TypeEnv); err != nil {
-			t.Fatal(err)
-		}
-		_, err := NewMessage()
-		assert.EqualError(err, fmt.Sprintf("Invalid %s", TypeEnv))
+		_, err := NewMessage(&config.Config{})
+		assert.EqualError(err, fmt.Sprintf("Invalid %s", config.IncomeTypeEnv))
 	})
 
 	t.Run("GitHub", func(t *testing.T) {
-		if err := os.Setenv(TypeEnv, GitHubType); err != nil {
-			t.Fatal(err)
+		cfg := &config.Config{
+			Income: config.Income{Type: config.GitHubType},
+			Store: config.Store{Dir: t.TempDir()},
+			Message: config.Message{StarMilestones: []int{100}, Mentions: map[string]string{"Codertocat": "U0123"}},
 		}
-		msg, err := NewMessage()
+		msg, err := NewMessage(cfg)
 		assert.Nil(err)
 		assert.IsType(msg, &GitHubMessage{})
+
+		gm := msg.(*GitHubMessage)
+		assert.Equal(gm.starMilestones, []int{100})
+		assert.Equal(gm.mentions, map[string]string{"codertocat": "U0123"})
+		assert.Nil(gm.api)
+		assert.Nil(gm.templates)
+		assert.Nil(gm.filter)
 	})
 
-	t.Cleanup(func(){
-		if err := os.Setenv(TypeEnv, beforeType); err != nil {
-			t.Fatal(err)
+	t.Run("invalid", func(t *testing.T) {
+		for _, c := range []struct {
+			cfg *config.Config
+			err string
+		}{
+			{
+				cfg: &config.Config{Message: config.Message{MarkdownFormat: "html"}},
+				err: `Invalid message.markdown_format (MARKDOWN_FORMAT): "html" is not supported`,
+			},
+			{
+				cfg: &config.Config{Filter: config.Filter{Rules: []string{"drop sender.type=Bot"}}},
+				err: `Invalid filter.rules (FILTER_RULES): "drop sender.type=Bot" is not a valid rule`,
+			},
+			{
+				cfg: &config.Config{QuietHours: config.QuietHours{Urgent: []string{"exclude event=deployment_status"}}},
+				err: `Invalid quiet_hours.urgent (QUIET_HOURS_URGENT): "exclude event=deployment_status" is not a valid include rule`,
+			},
+		} {
+			c.cfg.Income = config.Income{Type: config.GitHubType}
+			c.cfg.Store = config.Store{Dir: t.TempDir()}
+			_, err := NewMessage(c.cfg)
+			assert.EqualError(err, c.err)
 		}
 	})
 }
@@ -81,6 +120,38 @@ func TestGitHubMessageToPayload(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
 
+	t.Run("branch_protection_rule", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/branch_protection_rule.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "branch_protection_rule"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "ブランチ保護ルールが変更されました", true)
+		a.InsertField("対象ブランチ", "main")
+		a.InsertField("設定(変更前)", "required_approving_review_count: 1\n")
+		a.InsertField("設定(変更後)", "必須レビュー数: 2\nコードオーナーのレビュー: true\n管理者への適用: false\n必須ステータスチェック: build, test\nForce Push: off\nブランチ削除: off\n")
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
 	t.Run("commit_comment", func(t *testing.T) {
 		gm := GitHubMessage{}
 		json, err := os.ReadFile("./testdata/commit_comment.json")
@@ -172,6 +243,160 @@ func TestGitHubMessageToPayload(t *testing.T) {
 		assert.Equal(buf, ebuf)
 	})
 
+	t.Run("dependabot_alert", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/dependabot_alert.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "dependabot_alert"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.SetColor(builder.ErrorColor)
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "Dependabot のアラート (created)", true)
+		a.InsertField("アラート", "Command Injection in lodash")
+		a.InsertField("重要度", "high", true)
+		a.InsertField("パッケージ", "npm/lodash", true)
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World/security/dependabot/2")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+
+		// 解決された場合は色を付けない
+		body := `{"action":"fixed","alert":{"html_url":"https://github.com/Octocoders/Hello-World/security/code-scanning/3","rule":{"description":"Unused variable","severity":"warning"}},"ref":"refs/heads/main"}`
+		assert.Nil(gm.Init(map[string]string{EventHeader: "code_scanning_alert"}, &body))
+		buf, err = gm.ToPayload()
+		assert.Nil(err)
+
+		a = builder.NewAttachment()
+		a.InsertField("アカウント", "", true)
+		a.InsertField("アクション", "Code scanning のアラート (fixed)", true)
+		a.InsertField("アラート", "Unused variable")
+		a.InsertField("重要度", "warning", true)
+		a.InsertField("Ref", "refs/heads/main", true)
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World/security/code-scanning/3")
+		ebuf, err = a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("deployment_status", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/deployment_status.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "deployment_status"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.SetColor(builder.ErrorColor)
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "デプロイに失敗しました", true)
+		a.InsertField("環境", "production", true)
+		a.InsertField("Ref", "main", true)
+		a.InsertField("説明", "Health check failed")
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World/actions/runs/575913316")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+
+		// 完了していない場合は通知しない
+		body := `{"action":"created","deployment_status":{"state":"in_progress","environment":"production"}}`
+		assert.Nil(gm.Init(map[string]string{EventHeader: "deployment_status"}, &body))
+		buf, err = gm.ToPayload()
+		assert.Nil(err)
+		assert.Nil(buf)
+	})
+
+	t.Run("fork", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/fork.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "fork"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "フォークされました", true)
+		a.InsertField("フォーク先", "Codertocat/Hello-World")
+		a.InsertField("フォーク数", "1", true)
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("gollum", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/gollum.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "gollum"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "Wiki が更新されました", true)
+		a.InsertField("ページ", "[編集] <https://github.com/Octocoders/Hello-World/wiki/Home|Home> (<https://github.com/Octocoders/Hello-World/wiki/Home/_compare/6bf911d3801dd1ef957fc6ade5a8d96429e7fa39|差分>)\n[作成] <https://github.com/Octocoders/Hello-World/wiki/Release-Process|Release Process> Initial draft\n")
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World/wiki")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
 	t.Run("issue_comment", func(t *testing.T) {
 		gm := GitHubMessage{}
 		json, err := os.ReadFile("./testdata/issue_comment.json")
@@ -233,15 +458,15 @@ func TestGitHubMessageToPayload(t *testing.T) {
 		assert.Equal(buf, ebuf)
 	})
 
-	t.Run("pull_request", func(t *testing.T) {
+	t.Run("issues (transferred)", func(t *testing.T) {
 		gm := GitHubMessage{}
-		json, err := os.ReadFile("./testdata/pull_request.json")
+		json, err := os.ReadFile("./testdata/issues_transferred.json")
 		if err != nil {
 			t.Error(err)
 		}
 
 		err = gm.Init(
-			map[string]string{EventHeader: "pull_request"},
+			map[string]string{EventHeader: "issues"},
 			(*string)(unsafe.Pointer(&json)),
 		)
 		assert.Nil(err)
@@ -252,10 +477,11 @@ func TestGitHubMessageToPayload(t *testing.T) {
 
 		a := builder.NewAttachment()
 		a.InsertField("アカウント", "Codertocat", true)
-		a.InsertField("アクション", "PR がオープンされました", true)
-		a.InsertField("タイトル", "Update the README with new information.")
-		a.InsertField("内容", "This is a pretty simple change that we need to pull into master.")
-		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2")
+		a.InsertField("アクション", "Issue が譲渡されました", true)
+		a.InsertField("タイトル", "Spelling error in the README file")
+		a.InsertField("譲渡先", "<https://github.com/Octocoders/Hello-World|Octocoders/Hello-World>", true)
+		a.InsertField("リンク(譲渡前)", "https://github.com/Codertocat/Hello-World/issues/1")
+		a.InsertField("リンク(譲渡後)", "https://github.com/Octocoders/Hello-World/issues/5")
 		ebuf, err := a.Build()
 		if err != nil {
 			t.Error(err)
@@ -264,15 +490,15 @@ func TestGitHubMessageToPayload(t *testing.T) {
 		assert.Equal(buf, ebuf)
 	})
 
-	t.Run("pull_request_review", func(t *testing.T) {
+	t.Run("label", func(t *testing.T) {
 		gm := GitHubMessage{}
-		json, err := os.ReadFile("./testdata/pull_request_review.json")
+		json, err := os.ReadFile("./testdata/label.json")
 		if err != nil {
 			t.Error(err)
 		}
 
 		err = gm.Init(
-			map[string]string{EventHeader: "pull_request_review"},
+			map[string]string{EventHeader: "label"},
 			(*string)(unsafe.Pointer(&json)),
 		)
 		assert.Nil(err)
@@ -283,10 +509,12 @@ func TestGitHubMessageToPayload(t *testing.T) {
 
 		a := builder.NewAttachment()
 		a.InsertField("アカウント", "Codertocat", true)
-		a.InsertField("アクション", "PR のレビューがされました", true)
-		a.InsertField("タイトル", "Update the README with new information.")
-		a.InsertField("内容", "LGTM")
-		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2#pullrequestreview-237895671")
+		a.InsertField("アクション", "ラベルが編集されました", true)
+		a.InsertField("ラベル(変更前)", "review", true)
+		a.InsertField("ラベル(変更後)", "needs-review", true)
+		a.InsertField("色(変更前)", "#ededed", true)
+		a.InsertField("色(変更後)", "#d4c5f9", true)
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World/labels")
 		ebuf, err := a.Build()
 		if err != nil {
 			t.Error(err)
@@ -295,15 +523,15 @@ func TestGitHubMessageToPayload(t *testing.T) {
 		assert.Equal(buf, ebuf)
 	})
 
-	t.Run("pull_request_review_comment", func(t *testing.T) {
+	t.Run("marketplace_purchase", func(t *testing.T) {
 		gm := GitHubMessage{}
-		json, err := os.ReadFile("./testdata/pull_request_review_comment.json")
+		json, err := os.ReadFile("./testdata/marketplace_purchase.json")
 		if err != nil {
 			t.Error(err)
 		}
 
 		err = gm.Init(
-			map[string]string{EventHeader: "pull_request_review_comment"},
+			map[string]string{EventHeader: "marketplace_purchase"},
 			(*string)(unsafe.Pointer(&json)),
 		)
 		assert.Nil(err)
@@ -313,10 +541,11 @@ func TestGitHubMessageToPayload(t *testing.T) {
 		assert.IsType(buf, &bytes.Buffer{})
 
 		a := builder.NewAttachment()
-		a.InsertField("アカウント", "Codertocat", true)
-		a.InsertField("アクション", "PR にコメントされました", true)
-		a.InsertField("コメント", "Maybe you should use more emoji on this line.")
-		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2#discussion_r284312630")
+		a.InsertField("アカウント", "Octocoders", true)
+		a.InsertField("アクション", "Marketplace のプランが変更されました", true)
+		a.InsertField("プラン", "Pro ($19.99 / 月)")
+		a.InsertField("変更前", "Basic ($9.99 / 月)")
+		a.InsertField("適用日", "2019-05-01", true)
 		ebuf, err := a.Build()
 		if err != nil {
 			t.Error(err)
@@ -325,15 +554,15 @@ func TestGitHubMessageToPayload(t *testing.T) {
 		assert.Equal(buf, ebuf)
 	})
 
-	t.Run("pull_request_target", func(t *testing.T) {
+	t.Run("member", func(t *testing.T) {
 		gm := GitHubMessage{}
-		json, err := os.ReadFile("./testdata/pull_request_target.json")
+		json, err := os.ReadFile("./testdata/member.json")
 		if err != nil {
 			t.Error(err)
 		}
 
 		err = gm.Init(
-			map[string]string{EventHeader: "pull_request_target"},
+			map[string]string{EventHeader: "member"},
 			(*string)(unsafe.Pointer(&json)),
 		)
 		assert.Nil(err)
@@ -344,10 +573,11 @@ func TestGitHubMessageToPayload(t *testing.T) {
 
 		a := builder.NewAttachment()
 		a.InsertField("アカウント", "Codertocat", true)
-		a.InsertField("アクション", "PR がオープンされました", true)
-		a.InsertField("タイトル", "Update the README with new information.")
-		a.InsertField("内容", "This is a pretty simple change that we need to pull into master.")
-		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2")
+		a.InsertField("アクション", "コラボレーターの権限が変更されました", true)
+		a.InsertField("対象者", "Octocat")
+		a.InsertField("権限(変更前)", "write", true)
+		a.InsertField("権限(変更後)", "admin", true)
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World")
 		ebuf, err := a.Build()
 		if err != nil {
 			t.Error(err)
@@ -356,15 +586,15 @@ func TestGitHubMessageToPayload(t *testing.T) {
 		assert.Equal(buf, ebuf)
 	})
 
-	t.Run("push", func(t *testing.T) {
+	t.Run("membership", func(t *testing.T) {
 		gm := GitHubMessage{}
-		json, err := os.ReadFile("./testdata/push.json")
+		json, err := os.ReadFile("./testdata/membership.json")
 		if err != nil {
 			t.Error(err)
 		}
 
 		err = gm.Init(
-			map[string]string{EventHeader: "push"},
+			map[string]string{EventHeader: "membership"},
 			(*string)(unsafe.Pointer(&json)),
 		)
 		assert.Nil(err)
@@ -375,12 +605,42 @@ func TestGitHubMessageToPayload(t *testing.T) {
 
 		a := builder.NewAttachment()
 		a.InsertField("アカウント", "Codertocat", true)
-		a.InsertField("アクション", "プッシュされました", true)
-		a.InsertField("対象", "refs/tags/simple-tag")
-		a.InsertField("Commit",
-			"<https://github.com/Codertocat/Hello-World/commit/0123456789012345678901234567890123456789|0123456> Small Changes\n",
+		a.InsertField("アクション", "チームにメンバーが追加されました", true)
+		a.InsertField("チーム", "github")
+		a.InsertField("対象者", "Octocat")
+		a.InsertField("リンク", "https://github.com/orgs/Octocoders/teams/github")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("merge_group", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/merge_group.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "merge_group"},
+			(*string)(unsafe.Pointer(&json)),
 		)
-		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World")
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "マージキューのチェックが開始されました", true)
+		a.InsertField("ブランチ", "main", true)
+		a.InsertField("PR", "<https://github.com/Octocoders/Hello-World/pull/42|#42>", true)
+		a.InsertField("内容", "Merge pull request #42 from Octocoders/feature\n\nAdd feature")
+		a.InsertField("Commit", "<https://github.com/Octocoders/Hello-World/commit/ec4b2e9fa6c2f8e1c3a4b5d6e7f8091a2b3c4d5e|ec4b2e9>", true)
 		ebuf, err := a.Build()
 		if err != nil {
 			t.Error(err)
@@ -389,42 +649,2001 @@ func TestGitHubMessageToPayload(t *testing.T) {
 		assert.Equal(buf, ebuf)
 	})
 
-	t.Run("NOT targeted event", func(t *testing.T) {
+	t.Run("merge_group (destroyed)", func(t *testing.T) {
 		gm := GitHubMessage{}
-		json, err := os.ReadFile("./testdata/not_targeted.json")
+		json, err := os.ReadFile("./testdata/merge_group_destroyed.json")
 		if err != nil {
 			t.Error(err)
 		}
 
 		err = gm.Init(
-			map[string]string{EventHeader: "check_run"},
+			map[string]string{EventHeader: "merge_group"},
 			(*string)(unsafe.Pointer(&json)),
 		)
 		assert.Nil(err)
 
 		buf, err := gm.ToPayload()
 		assert.Nil(err)
-		assert.Nil(buf)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.Color = toP(builder.ErrorColor)
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "マージキューから外されました", true)
+		a.InsertField("理由", "dequeued", true)
+		a.InsertField("ブランチ", "main", true)
+		a.InsertField("PR", "<https://github.com/Octocoders/Hello-World/pull/42|#42>", true)
+		a.InsertField("内容", "Merge pull request #42 from Octocoders/feature\n\nAdd feature")
+		a.InsertField("Commit", "<https://github.com/Octocoders/Hello-World/commit/ec4b2e9fa6c2f8e1c3a4b5d6e7f8091a2b3c4d5e|ec4b2e9>", true)
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
 	})
-}
 
-func TestGitHubMessageToDummyPayload(t *testing.T) {
-	t.Parallel()
-	assert := assert.New(t)
+	t.Run("milestone", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/milestone.json")
+		if err != nil {
+			t.Error(err)
+		}
 
-	gm := GitHubMessage{}
-	buf, err := gm.ToDummyPayload()
-	assert.Nil(err)
-	assert.IsType(buf, &bytes.Buffer{})
+		err = gm.Init(
+			map[string]string{EventHeader: "milestone"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
 
-	a := builder.NewAttachment()
-	a.InsertField("アカウント", "Bot", true)
-	a.InsertField("アクション", "Invoke", true)
-	a.InsertField("内容", "OK")
-	ebuf, err := a.Build()
-	if err != nil {
-		t.Error(err)
-	}
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
 
-	assert.Equal(buf, ebuf)
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "マイルストーンがクローズされました", true)
+		a.InsertField("マイルストーン", "Sprint 12")
+		a.InsertField("Issue (オープン)", "1", true)
+		a.InsertField("Issue (クローズ)", "3", true)
+		a.InsertField("進捗", "75%", true)
+		a.InsertField("期限", "2019-05-31", true)
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World/milestone/1")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("package", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/package.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "package"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "パッケージが公開されました", true)
+		a.InsertField("パッケージ", "<https://github.com/Octocoders/Hello-World/packages/10696|hello-world-npm>", true)
+		a.InsertField("種類", "npm", true)
+		a.InsertField("バージョン", "1.0.0", true)
+		a.InsertField("アップロード", "Codertocat", true)
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World/packages/10696?version=1.0.0")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("page_build", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/page_build.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "page_build"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.Color = toP(builder.ErrorColor)
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "GitHub Pages のビルドに失敗しました", true)
+		a.InsertField("エラー", "Page build failed: The tag `endif` on line 12 in `_layouts/default.html` was not properly closed.")
+		a.InsertField("Commit", "<https://github.com/Octocoders/Hello-World/commit/507fc9acd0d04ac4a9db87d0cb00e2cb7c7a5a0a|507fc9a>", true)
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World/settings/pages")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("ping", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/ping.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "ping"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.SetColor(builder.WarningColor)
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "WebHook が接続されました", true)
+		a.InsertField("Hook ID", "30", true)
+		a.InsertField("リポジトリ", "Octocoders/Hello-World", true)
+		a.InsertField("Event", "push, pull_request, check_run")
+		a.InsertField("警告", "以下の Event は通知されません: check_run")
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("projects_v2_item", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/projects_v2_item.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "projects_v2_item"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "プロジェクトのアイテムが更新されました", true)
+		a.InsertField("フィールド", "Status", true)
+		a.InsertField("値(変更前)", "Todo", true)
+		a.InsertField("値(変更後)", "In Progress", true)
+		a.InsertField("種類", "Issue", true)
+		a.InsertField("リンク", "https://github.com/orgs/Octocoders/projects/3")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("pull_request", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/pull_request.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "pull_request"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "PR がオープンされました", true)
+		a.InsertField("タイトル", "Update the README with new information.")
+		a.InsertField("内容", "This is a pretty simple change that we need to pull into master.")
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("pull_request (converted_to_draft)", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/pull_request_converted_to_draft.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "pull_request"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "PR が Draft に変更されました", true)
+		a.InsertField("タイトル", "Update the README with new information.")
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("pull_request (ready_for_review)", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/pull_request_ready_for_review.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "pull_request"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "PR の準備が整いました", true)
+		a.InsertField("タイトル", "Update the README with new information.")
+		a.InsertField("作成者", "Codertocat", true)
+		a.InsertField("レビュアー", "Octocat, reviewers", true)
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("pull_request (synchronize)", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/pull_request_synchronize.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "pull_request"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "PR にコミットがプッシュされました", true)
+		a.InsertField("タイトル", "Update the README with new information.")
+		a.InsertField("変更", "<https://github.com/Codertocat/Hello-World/compare/ec26c3e57ca3a959ca5aad62de7213c562f8c821...34c5c7793cb3b279e22454cb6750c80560547b3a|ec26c3e...34c5c77>")
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("pull_request_review", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/pull_request_review.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "pull_request_review"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.SetColor(builder.CommentedColor)
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", ":speech_balloon: PR にレビューコメントされました", true)
+		a.InsertField("タイトル", "Update the README with new information.")
+		a.InsertField("内容", "LGTM")
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2#pullrequestreview-237895671")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("pull_request_review_comment", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/pull_request_review_comment.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "pull_request_review_comment"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "PR にコメントされました", true)
+		a.InsertField("コメント", "Maybe you should use more emoji on this line.")
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2#discussion_r284312630")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("pull_request_target", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/pull_request_target.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "pull_request_target"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "PR がオープンされました", true)
+		a.InsertField("タイトル", "Update the README with new information.")
+		a.InsertField("内容", "This is a pretty simple change that we need to pull into master.")
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("push", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/push.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "push"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "タグが削除されました", true)
+		a.InsertField("対象", "refs/tags/simple-tag")
+		a.InsertField("削除前",
+			"<https://github.com/Codertocat/Hello-World/commit/6113728f27ae82c7b1a177c8d03f9e96e0adf246|6113728>",
+		)
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("registry_package", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/registry_package.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "registry_package"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "パッケージが公開されました", true)
+		a.InsertField("パッケージ", "<https://github.com/orgs/Octocoders/packages/container/package/hello-world|hello-world>", true)
+		a.InsertField("種類", "container", true)
+		a.InsertField("バージョン", "v1.2.0", true)
+		a.InsertField("アップロード", "Codertocat", true)
+		a.InsertField("ダイジェスト", "sha256:3c4f1f0f3e6b4f8f8e2f5a1d2b7c9e0a4d6f8b1c3e5a7d9f0b2c4e6a8d0f1b3c")
+		a.InsertField("リンク", "https://github.com/orgs/Octocoders/packages/container/hello-world/88214031")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("repository", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/repository.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "repository"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "リポジトリ名が変更されました", true)
+		a.InsertField("リポジトリ名(変更前)", "Hello-World")
+		a.InsertField("リポジトリ名(変更後)", "Hello-Universe")
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-Universe")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("repository_ruleset", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/repository_ruleset.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "repository_ruleset"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "ルールセットが作成されました", true)
+		a.InsertField("ルールセット", "protect-main")
+		a.InsertField("対象", "branch", true)
+		a.InsertField("適用状態", "active", true)
+		a.InsertField("ルール", "deletion, non_fast_forward, pull_request")
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World/rules/42")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("sponsorship", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/sponsorship.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "sponsorship"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("スポンサー", "octocat", true)
+		a.InsertField("アクション", "ティアが変更されました", true)
+		a.InsertField("対象", "Codertocat", true)
+		a.InsertField("ティア", "$10 a month ($10 / 月)", true)
+		a.InsertField("変更前", "$5 a month ($5 / 月)", true)
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("star", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/star.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "star"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "スターが付けられました", true)
+		a.InsertField("リポジトリ", "Octocoders/Hello-World")
+		a.InsertField("スター数", "42", true)
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("team", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/team.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "team"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "チームが編集されました", true)
+		a.InsertField("チーム", "github")
+		a.InsertField("公開範囲(変更前)", "closed", true)
+		a.InsertField("公開範囲(変更後)", "secret", true)
+		a.InsertField("リンク", "https://github.com/orgs/Octocoders/teams/github")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("team_add", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/team_add.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "team_add"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "チームにリポジトリが追加されました", true)
+		a.InsertField("チーム", "github", true)
+		a.InsertField("リポジトリ", "Octocoders/Hello-World", true)
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/watch.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "watch"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.IsType(buf, &bytes.Buffer{})
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "スターが付けられました", true)
+		a.InsertField("リポジトリ", "Octocoders/Hello-World")
+		a.InsertField("スター数", "100", true)
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("NOT targeted event", func(t *testing.T) {
+		gm := GitHubMessage{}
+		json, err := os.ReadFile("./testdata/not_targeted.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "check_run"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Nil(buf)
+	})
+}
+
+func TestGitHubMessageDraftPullRequest(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	json, err := os.ReadFile("./testdata/pull_request_draft.json")
+	if err != nil {
+		t.Error(err)
+	}
+	body := string(json)
+
+	expected := func(draft bool) *bytes.Buffer {
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "PR がオープンされました", true)
+		a.InsertField("タイトル", "Update the README with new information.")
+		a.InsertField("内容", "This is a pretty simple change that we need to pull into master.")
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2")
+		if draft {
+			a.SetColor(builder.DraftColor)
+			a.InsertField("状態", "Draft", true)
+		}
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+		return ebuf
+	}
+
+	t.Run("mute (default)", func(t *testing.T) {
+		gm := GitHubMessage{}
+		err := gm.Init(map[string]string{EventHeader: "pull_request"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Equal(buf, expected(true))
+	})
+
+	t.Run("suppress", func(t *testing.T) {
+		gm := GitHubMessage{draftMode: DraftSuppress}
+		err := gm.Init(map[string]string{EventHeader: "pull_request"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Nil(buf)
+		// ダイジェストにも記録しない
+		assert.Nil(gm.Digest())
+	})
+
+	t.Run("show", func(t *testing.T) {
+		gm := GitHubMessage{draftMode: DraftShow}
+		err := gm.Init(map[string]string{EventHeader: "pull_request"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Equal(buf, expected(false))
+	})
+
+	t.Run("converted_to_draft is not suppressed", func(t *testing.T) {
+		json, err := os.ReadFile("./testdata/pull_request_converted_to_draft.json")
+		if err != nil {
+			t.Error(err)
+		}
+		body := string(json)
+
+		gm := GitHubMessage{draftMode: DraftSuppress}
+		err = gm.Init(map[string]string{EventHeader: "pull_request"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.NotNil(buf)
+	})
+}
+
+func TestGitHubMessagePush(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	t.Run("forced", func(t *testing.T) {
+		json, err := os.ReadFile("./testdata/push_forced.json")
+		if err != nil {
+			t.Error(err)
+		}
+		body := string(json)
+
+		gm := GitHubMessage{pushCommitLimit: 2, mentions: map[string]string{"octocat@example.com": "U0123"}}
+		err = gm.Init(map[string]string{EventHeader: "push"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.SetColor(builder.ForcePushColor)
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", ":warning: Force Push されました", true)
+		a.InsertField("対象", "refs/heads/main")
+		a.InsertField("変更", "<https://github.com/Codertocat/Hello-World/compare/6113728f27ae...1a2b3c4d5e6f|6113728...1a2b3c4>")
+		a.InsertField("Commit",
+			"<https://github.com/Codertocat/Hello-World/commit/0000000000000000000000000000000000000000|0000000> Commit 1 (Codertocat)\n"+
+				"<https://github.com/Codertocat/Hello-World/commit/1111111111111111111111111111111111111111|1111111> Commit 2 (Codertocat, <@U0123>)\n"+
+				"<https://github.com/Codertocat/Hello-World/compare/6113728f27ae...1a2b3c4d5e6f|…他 3 件のコミット>\n",
+		)
+		a.InsertField("作成者", "Codertocat (5), <@U0123> (1)")
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("created without commits", func(t *testing.T) {
+		json, err := os.ReadFile("./testdata/push_created.json")
+		if err != nil {
+			t.Error(err)
+		}
+		body := string(json)
+
+		gm := GitHubMessage{}
+		err = gm.Init(map[string]string{EventHeader: "push"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "プッシュされました", true)
+		a.InsertField("対象", "refs/heads/feature")
+		a.InsertField("変更", "<https://github.com/Codertocat/Hello-World/compare/feature|6113728>")
+		a.InsertField("Commit", "<https://github.com/Codertocat/Hello-World/commit/6113728f27ae82c7b1a177c8d03f9e96e0adf246|6113728> Initial commit (Codertocat)\n")
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+}
+
+func TestParseCoAuthors(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	message := "Pair on parser\n\nCo-authored-by: Octocat <583231+octocat@users.noreply.github.com>\nco-authored-by: Mona <mona@example.com>"
+	assert.Equal(parseCoAuthors(message), []commitAuthor{
+		{name: "Octocat", email: "583231+octocat@users.noreply.github.com", login: "octocat"},
+		{name: "Mona", email: "mona@example.com"},
+	})
+	assert.Equal(stripCoAuthors(message), "Pair on parser")
+}
+
+func TestNormalizeMentions(t *testing.T) {
+	t.Parallel()
+
+	mentions := normalizeMentions(map[string]string{"Codertocat": "U0123", "octocat@example.com": "U0456"})
+	assert.Equal(t, mentions, map[string]string{"codertocat": "U0123", "octocat@example.com": "U0456"})
+}
+
+func TestGitHubMessageIssueTransferred(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	// changes (new_issue, new_repository) を含まない payload
+	json, err := os.ReadFile("./testdata/issues.json")
+	if err != nil {
+		t.Error(err)
+	}
+	body := strings.Replace(string(json), `"action": "edited"`, `"action": "transferred"`, 1)
+
+	t.Run("with API", func(t *testing.T) {
+		c := &api.MockedClient{}
+		c.On("GetIssue", "Codertocat", "Hello-World", 1).
+			Return(&github.Issue{HTMLURL: toP("https://github.com/Octocoders/Hello-World/issues/5")}, nil)
+		gm := GitHubMessage{api: c}
+		err := gm.Init(map[string]string{EventHeader: "issues"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "Issue が譲渡されました", true)
+		a.InsertField("タイトル", "Spelling error in the README file")
+		a.InsertField("譲渡先", "<https://github.com/Octocoders/Hello-World|Octocoders/Hello-World>", true)
+		a.InsertField("リンク(譲渡前)", "https://github.com/Codertocat/Hello-World/issues/1")
+		a.InsertField("リンク(譲渡後)", "https://github.com/Octocoders/Hello-World/issues/5")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(buf, ebuf)
+		c.AssertExpectations(t)
+	})
+
+	t.Run("without API", func(t *testing.T) {
+		gm := GitHubMessage{}
+		err := gm.Init(map[string]string{EventHeader: "issues"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "Issue が譲渡されました", true)
+		a.InsertField("タイトル", "Spelling error in the README file")
+		a.InsertField("リンク(譲渡前)", "https://github.com/Codertocat/Hello-World/issues/1")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(buf, ebuf)
+	})
+}
+
+func TestGitHubMessageUnhandledEvent(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	release := `{
+		"action": "published",
+		"release": {"html_url": "https://github.com/Codertocat/Hello-World/releases/tag/0.0.1", "name": "v0.0.1"},
+		"repository": {"full_name": "Codertocat/Hello-World", "html_url": "https://github.com/Codertocat/Hello-World"},
+		"sender": {"login": "Codertocat"}
+	}`
+	// go-github (v38) が対応していない Event
+	unknown := `{
+		"action": "updated",
+		"organization": {"login": "Octocoders"},
+		"sender": {"login": "Codertocat"}
+	}`
+
+	t.Run("disabled", func(t *testing.T) {
+		gm := GitHubMessage{}
+		err := gm.Init(map[string]string{EventHeader: "release"}, &release)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Nil(buf)
+
+		err = gm.Init(map[string]string{EventHeader: "custom_property_values"}, &unknown)
+		assert.NotNil(err)
+	})
+
+	t.Run("go-github event", func(t *testing.T) {
+		gm := GitHubMessage{renderUnhandled: true}
+		err := gm.Init(map[string]string{EventHeader: "release"}, &release)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "release (published)", true)
+		a.InsertField("リポジトリ", "Codertocat/Hello-World", true)
+		a.InsertField("タイトル", "v0.0.1")
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/releases/tag/0.0.1")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		gm := GitHubMessage{renderUnhandled: true}
+		err := gm.Init(map[string]string{EventHeader: "custom_property_values"}, &unknown)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "custom_property_values (updated)", true)
+		a.InsertField("Organization", "Octocoders", true)
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(buf, ebuf)
+	})
+}
+
+func TestGitHubMessageTemplate(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	text := `{{field "アカウント" (mention .sender.login) true}}{{field "タイトル" .issue.title}}`
+	if err := os.WriteFile(filepath.Join(dir, "issues.edited.tmpl"), []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &template.FileTemplates{}
+	if err := tmpl.Init(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	json, err := os.ReadFile("./testdata/issues.json")
+	if err != nil {
+		t.Error(err)
+	}
+	body := string(json)
+
+	t.Run("with template", func(t *testing.T) {
+		gm := GitHubMessage{templates: tmpl, mentions: map[string]string{"codertocat": "U0123"}}
+		err := gm.Init(map[string]string{EventHeader: "issues"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "<@U0123>", true)
+		a.InsertField("タイトル", "Spelling error in the README file")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("without template", func(t *testing.T) {
+		gm := GitHubMessage{templates: tmpl}
+		json, err := os.ReadFile("./testdata/push.json")
+		if err != nil {
+			t.Error(err)
+		}
+		body := string(json)
+		err = gm.Init(map[string]string{EventHeader: "push"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Contains(buf.String(), "削除")
+	})
+}
+
+func TestGitHubMessageMarkdown(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	json, err := os.ReadFile("./testdata/pull_request.json")
+	if err != nil {
+		t.Error(err)
+	}
+	// PR テンプレートに沿って記載された本文
+	body := strings.Replace(
+		string(json),
+		`"This is a pretty simple change that we need to pull into master."`,
+		`"## 概要\r\n<!-- 変更内容を記載してください -->\r\n**README** の [誤字](https://github.com/Codertocat/Hello-World/issues/1) を修正\r\n\r\n## 備考\r\n<!-- 任意 -->\r\n"`,
+		1,
+	)
+
+	for _, c := range []struct {
+		format string
+		value string
+	}{
+		{"", "*概要*\n\n*README* の <https://github.com/Codertocat/Hello-World/issues/1|誤字> を修正"},
+		{markdown.Plain, "概要\n\nREADME の 誤字 (https://github.com/Codertocat/Hello-World/issues/1) を修正"},
+	} {
+		gm := GitHubMessage{markdownFormat: c.format}
+		err := gm.Init(map[string]string{EventHeader: "pull_request"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "PR がオープンされました", true)
+		a.InsertField("タイトル", "Update the README with new information.")
+		a.InsertField("内容", c.value)
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(buf, ebuf)
+	}
+}
+
+func TestFilterEvent(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	for _, c := range []struct {
+		eventType string
+		file string
+		expected *filter.Event
+	}{
+		{
+			"push", "push.json",
+			&filter.Event{Sender: "Codertocat", SenderType: "User", Event: "push", Ref: "refs/tags/simple-tag", Paths: []string{"README.md"}, Repository: "Codertocat/Hello-World"},
+		},
+		{
+			"create", "create.json",
+			&filter.Event{Sender: "Codertocat", SenderType: "User", Event: "create", Ref: "refs/tags/simple-tag", Repository: "Codertocat/Hello-World"},
+		},
+		{
+			"pull_request", "pull_request.json",
+			&filter.Event{Sender: "Codertocat", SenderType: "User", Event: "pull_request", Action: "opened", Ref: "refs/heads/master", Repository: "Codertocat/Hello-World"},
+		},
+		{
+			"issues", "issues.json",
+			&filter.Event{Sender: "Codertocat", SenderType: "User", Event: "issues", Action: "edited", Labels: []string{"bug"}, Repository: "Codertocat/Hello-World"},
+		},
+	} {
+		json, err := os.ReadFile(filepath.Join("./testdata", c.file))
+		if err != nil {
+			t.Error(err)
+		}
+		body := string(json)
+		gm := GitHubMessage{}
+		err = gm.Init(map[string]string{EventHeader: c.eventType}, &body)
+		assert.Nil(err)
+
+		data, err := eventData(gm.event)
+		assert.Nil(err)
+		assert.Equal(filterEvent(gm.eventType, data), c.expected, c.eventType)
+	}
+}
+
+func TestGitHubMessageFilter(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	json, err := os.ReadFile("./testdata/push.json")
+	if err != nil {
+		t.Error(err)
+	}
+	body := string(json)
+
+	t.Run("excluded", func(t *testing.T) {
+		f := &filter.RuleFilter{}
+		assert.Nil(f.Init([]string{"exclude event=push path=*.md"}, false))
+		gm := GitHubMessage{filter: f}
+		err := gm.Init(map[string]string{EventHeader: "push"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Nil(buf)
+		// ダイジェストにも記録しない
+		assert.Nil(gm.Digest())
+	})
+
+	t.Run("not excluded", func(t *testing.T) {
+		f := &filter.MockedFilter{}
+		f.On("Allow", &filter.Event{Sender: "Codertocat", SenderType: "User", Event: "push", Ref: "refs/tags/simple-tag", Paths: []string{"README.md"}, Repository: "Codertocat/Hello-World"}).Return(true)
+		gm := GitHubMessage{filter: f}
+		err := gm.Init(map[string]string{EventHeader: "push"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.NotNil(buf)
+		assert.NotNil(gm.Digest())
+		f.AssertExpectations(t)
+	})
+}
+
+func TestGitHubMessagePackageFilter(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	json, err := os.ReadFile("./testdata/registry_package.json")
+	if err != nil {
+		t.Error(err)
+	}
+	body := string(json)
+
+	t.Run("matched", func(t *testing.T) {
+		gm := GitHubMessage{packageFilter: []string{"hello-*"}}
+		err := gm.Init(map[string]string{EventHeader: "registry_package"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.NotNil(buf)
+	})
+
+	t.Run("not matched", func(t *testing.T) {
+		gm := GitHubMessage{packageFilter: []string{"other"}}
+		err := gm.Init(map[string]string{EventHeader: "registry_package"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Nil(buf)
+		// ダイジェストにも記録しない
+		assert.Nil(gm.Digest())
+
+		// 次の Event には引き継がない
+		assert.Nil(gm.Init(map[string]string{EventHeader: "registry_package"}, &body))
+		gm.packageFilter = nil
+		buf, err = gm.ToPayload()
+		assert.Nil(err)
+		assert.NotNil(buf)
+		assert.NotNil(gm.Digest())
+	})
+}
+
+func TestGitHubMessagePullRequestSynchronize(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	json, err := os.ReadFile("./testdata/pull_request_synchronize.json")
+	if err != nil {
+		t.Error(err)
+	}
+	body := string(json)
+	compare := "<https://github.com/Codertocat/Hello-World/compare/ec26c3e57ca3a959ca5aad62de7213c562f8c821...34c5c7793cb3b279e22454cb6750c80560547b3a|ec26c3e...34c5c77>"
+
+	t.Run("with API", func(t *testing.T) {
+		// GitHub API のスタブ
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(r.URL.Path, "/repos/Codertocat/Hello-World/compare/ec26c3e57ca3a959ca5aad62de7213c562f8c821...34c5c7793cb3b279e22454cb6750c80560547b3a")
+			fmt.Fprint(w, `{"commits":[
+				{"sha":"1d3b1e1c3e3f1b38ff2c2cde5c1e0a4b96f0f1b7","html_url":"https://github.com/Codertocat/Hello-World/commit/1d3b1e1c3e3f1b38ff2c2cde5c1e0a4b96f0f1b7","commit":{"message":"Fix typo"}},
+				{"sha":"34c5c7793cb3b279e22454cb6750c80560547b3a","html_url":"https://github.com/Codertocat/Hello-World/commit/34c5c7793cb3b279e22454cb6750c80560547b3a","commit":{"message":"Add tests"}}
+			]}`)
+		}))
+		t.Cleanup(s.Close)
+
+		c := &api.GitHubClient{}
+		if err := c.Init("xxx", s.URL); err != nil {
+			t.Fatal(err)
+		}
+		gm := GitHubMessage{api: c}
+		err := gm.Init(map[string]string{EventHeader: "pull_request"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", "PR にコミットがプッシュされました", true)
+		a.InsertField("タイトル", "Update the README with new information.")
+		a.InsertField("変更", compare)
+		a.InsertField("Commit",
+			"<https://github.com/Codertocat/Hello-World/commit/1d3b1e1c3e3f1b38ff2c2cde5c1e0a4b96f0f1b7|1d3b1e1> Fix typo\n"+
+				"<https://github.com/Codertocat/Hello-World/commit/34c5c7793cb3b279e22454cb6750c80560547b3a|34c5c77> Add tests\n",
+		)
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		c := &api.MockedClient{}
+		c.On("CompareCommits", "Codertocat", "Hello-World", "ec26c3e57ca3a959ca5aad62de7213c562f8c821", "34c5c7793cb3b279e22454cb6750c80560547b3a").
+			Return(nil, fmt.Errorf("mocked"))
+		gm := GitHubMessage{api: c}
+		err := gm.Init(map[string]string{EventHeader: "pull_request"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Contains(buf.String(), "ec26c3e...34c5c77")
+		assert.NotContains(buf.String(), "Commit")
+	})
+}
+
+func TestGitHubMessagePullRequestReview(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	t.Run("approved", func(t *testing.T) {
+		json, err := os.ReadFile("./testdata/pull_request_review_approved.json")
+		if err != nil {
+			t.Error(err)
+		}
+		body := string(json)
+
+		c := &api.MockedClient{}
+		c.On("CountReviewComments", "Codertocat", "Hello-World", 2, int64(237895671)).Return(3, nil)
+		gm := GitHubMessage{api: c}
+		err = gm.Init(map[string]string{EventHeader: "pull_request_review"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.SetColor(builder.ApprovedColor)
+		a.InsertField("アカウント", "Codertocat", true)
+		a.InsertField("アクション", ":white_check_mark: PR が承認されました", true)
+		a.InsertField("タイトル", "Update the README with new information.")
+		a.InsertField("内容", "Looks great!")
+		a.InsertField("インラインコメント", "3 件", true)
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2#pullrequestreview-237895671")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("dismissed", func(t *testing.T) {
+		json, err := os.ReadFile("./testdata/pull_request_review_dismissed.json")
+		if err != nil {
+			t.Error(err)
+		}
+		body := string(json)
+
+		c := &api.MockedClient{}
+		c.On("GetDismissalMessage", "Codertocat", "Hello-World", 2, int64(237895671)).Return("Outdated review", nil)
+		gm := GitHubMessage{api: c}
+		err = gm.Init(map[string]string{EventHeader: "pull_request_review"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.SetColor(builder.DismissedColor)
+		a.InsertField("アカウント", "Octocat", true)
+		a.InsertField("アクション", ":no_entry_sign: PR のレビューが却下されました", true)
+		a.InsertField("レビュアー", "Codertocat", true)
+		a.InsertField("タイトル", "Update the README with new information.")
+		a.InsertField("理由", "Outdated review")
+		a.InsertField("リンク", "https://github.com/Codertocat/Hello-World/pull/2#pullrequestreview-237895671")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+}
+
+func TestGitHubMessageStarred(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	json, err := os.ReadFile("./testdata/watch.json")
+	if err != nil {
+		t.Error(err)
+	}
+	body := string(json)
+
+	t.Run("milestone", func(t *testing.T) {
+		gm := GitHubMessage{starMilestones: []int{100, 500}}
+		err := gm.Init(map[string]string{EventHeader: "watch"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.SetColor(builder.CelebrationColor)
+		a.InsertField("アクション", ":tada: スター数が 100 に到達しました")
+		a.InsertField("リポジトリ", "Octocoders/Hello-World")
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("milestone only once", func(t *testing.T) {
+		s := &store.FileStore{}
+		if err := s.Init(t.TempDir()); err != nil {
+			t.Fatal(err)
+		}
+		gm := GitHubMessage{store: s, starMilestones: []int{100}}
+		err := gm.Init(map[string]string{EventHeader: "watch"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Contains(buf.String(), "スター数が 100 に到達しました")
+
+		buf, err = gm.ToPayload()
+		assert.Nil(err)
+		assert.Contains(buf.String(), "スターが付けられました")
+	})
+
+	t.Run("milestone crossed", func(t *testing.T) {
+		s := &store.FileStore{}
+		if err := s.Init(t.TempDir()); err != nil {
+			t.Fatal(err)
+		}
+		gm := GitHubMessage{store: s, starMilestones: []int{50, 100, 500}}
+		for _, count := range []string{"99", "101", "102"} {
+			b := strings.Replace(body, `"stargazers_count": 100`, `"stargazers_count": `+count, 1)
+			assert.Nil(gm.Init(map[string]string{EventHeader: "watch"}, &b))
+			buf, err := gm.ToPayload()
+			assert.Nil(err)
+			switch count {
+			case "99":
+				// 50 は既に超えているが、最初に超えた時点で通知していない場合も一度のみ通知する
+				assert.Contains(buf.String(), "スター数が 50 に到達しました", count)
+			case "101":
+				// 100 ちょうどの通知を逃しても、超えた場合に通知する
+				assert.Contains(buf.String(), "スター数が 100 に到達しました", count)
+			default:
+				assert.Contains(buf.String(), "スターが付けられました", count)
+			}
+		}
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		s := &store.FileStore{}
+		if err := s.Init(t.TempDir()); err != nil {
+			t.Fatal(err)
+		}
+		gm := GitHubMessage{store: s, starBatchSize: 3}
+		err := gm.Init(map[string]string{EventHeader: "watch"}, &body)
+		assert.Nil(err)
+
+		for i := 0; i < 2; i++ {
+			buf, err := gm.ToPayload()
+			assert.Nil(err)
+			assert.Nil(buf)
+		}
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.InsertField("アクション", "本日 3 件のスターが付きました", true)
+		a.InsertField("スター数", "100", true)
+		a.InsertField("リポジトリ", "Octocoders/Hello-World")
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("batch rest", func(t *testing.T) {
+		s := &store.FileStore{}
+		if err := s.Init(t.TempDir()); err != nil {
+			t.Fatal(err)
+		}
+		// 前回は 2021-10-01 に 5 件 (3 件ごとに通知したため 2 件は未通知)
+		assert.Nil(s.Set("star_batch_day/Octocoders/Hello-World", []byte("2021-10-01")))
+		assert.Nil(s.Set("star_batch/Octocoders/Hello-World/2021-10-01", []byte("5")))
+		gm := GitHubMessage{store: s, starBatchSize: 3}
+		err := gm.Init(map[string]string{EventHeader: "watch"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.InsertField("アクション", "2021-10-01 に 2 件のスターが付きました", true)
+		a.InsertField("スター数", "100", true)
+		a.InsertField("リポジトリ", "Octocoders/Hello-World")
+		a.InsertField("リンク", "https://github.com/Octocoders/Hello-World")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(buf, ebuf)
+
+		// 本日分は引き続き集計する
+		buf, err = gm.ToPayload()
+		assert.Nil(err)
+		assert.Nil(buf)
+	})
+
+	// 同じスターの star・watch は一度のみ数える
+	t.Run("star and watch", func(t *testing.T) {
+		s := &store.FileStore{}
+		if err := s.Init(t.TempDir()); err != nil {
+			t.Fatal(err)
+		}
+		star, err := os.ReadFile("./testdata/star.json")
+		if err != nil {
+			t.Error(err)
+		}
+		gm := GitHubMessage{store: s, starBatchSize: 2}
+		assert.Nil(gm.Init(map[string]string{EventHeader: "star"}, (*string)(unsafe.Pointer(&star))))
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Nil(buf)
+
+		assert.Nil(gm.Init(map[string]string{EventHeader: "watch"}, &body))
+		buf, err = gm.ToPayload()
+		assert.Nil(err)
+		assert.Nil(buf)
+		assert.Nil(gm.Digest())
+		n, err := s.Get(starBatchKey(gm.event.(*watchEvent).GetRepo(), time.Now()))
+		assert.Nil(err)
+		assert.Equal(string(n), "1")
+
+		// 別のスターとして数える
+		buf, err = gm.ToPayload()
+		assert.Nil(err)
+		assert.Contains(buf.String(), "本日 2 件のスターが付きました")
+	})
+
+	t.Run("batch key", func(t *testing.T) {
+		gm := GitHubMessage{}
+		err := gm.Init(map[string]string{EventHeader: "watch"}, &body)
+		assert.Nil(err)
+
+		e := gm.event.(*watchEvent)
+		now := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+		assert.Equal(starBatchKey(e.GetRepo(), now), "star_batch/Octocoders/Hello-World/2021-10-01")
+	})
+}
+
+func TestRenderedEvents(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	body := `{}`
+
+	for _, e := range renderedEvents {
+		gm := GitHubMessage{}
+		err := gm.Init(map[string]string{EventHeader: e}, &body)
+		assert.Nil(err, e)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err, e)
+		assert.NotNil(buf, e)
+	}
+}
+
+func TestUnrenderedEvents(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	ja := i18n.NewLocalizer("ja")
+
+	assert.Nil(unrenderedEvents(ja, []string{"push", "issues"}))
+	assert.Equal(unrenderedEvents(ja, []string{"push", "check_run", "status"}), []string{"check_run", "status"})
+	assert.Equal(unrenderedEvents(ja, []string{"*"}), []string{"* (すべての Event)"})
+}
+
+// 日本語で同じ文言の項目も、英語では区別する
+func TestEnglishLabels(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	en := i18n.NewLocalizer("en")
+
+	json, err := os.ReadFile("./testdata/projects_v2_item.json")
+	if err != nil {
+		t.Error(err)
+	}
+	gm := GitHubMessage{}
+	assert.Nil(gm.Init(map[string]string{EventHeader: "projects_v2_item"}, (*string)(unsafe.Pointer(&json))))
+	buf, err := buildProjectsV2ItemEvent(en, gm.event.(*projectsV2ItemEvent))
+	assert.Nil(err)
+	assert.Contains(buf.String(), `{"title":"Content type","value":"Issue","short":true}`)
+
+	level := "off"
+	assert.Contains(branchProtectionRuleSummary(en, &branchProtectionRule{AllowForcePushesEnforcementLevel: &level}), "Force push: off\n")
+
+	name, unit := "Pro", "seat"
+	count, price := 3, 1000
+	model := "per-unit"
+	p := &github.MarketplacePurchase{
+		UnitCount: &count,
+		Plan: &github.MarketplacePlan{Name: &name, UnitName: &unit, MonthlyPriceInCents: &price, PriceModel: &model},
+	}
+	assert.Equal(marketplacePlan(en, p), "Pro ($10 / month) × 3 seat")
+}
+
+func TestProjectFieldValue(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	ja := i18n.NewLocalizer("ja")
+
+	assert.Equal(projectFieldValue(ja, nil), "(なし)")
+	assert.Equal(projectFieldValue(ja, []byte(`null`)), "(なし)")
+	assert.Equal(projectFieldValue(ja, []byte(`"2022-11-30"`)), "2022-11-30")
+	assert.Equal(projectFieldValue(ja, []byte(`3`)), "3")
+	assert.Equal(projectFieldValue(ja, []byte(`{"id":"f75ad846","name":"Todo"}`)), "Todo")
+	assert.Equal(projectFieldValue(ja, []byte(`{"id":"cfc16e4d","title":"Iteration 1"}`)), "Iteration 1")
+}
+
+func TestGitHubMessageRoute(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	routes := config.Routes{Audit: "audit", Sponsor: "sponsor"}
+
+	t.Run("audit event", func(t *testing.T) {
+		gm := GitHubMessage{routes: routes}
+		json, err := os.ReadFile("./testdata/member.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "member"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+		assert.Equal(gm.Route(), "audit")
+	})
+
+	t.Run("sponsor event", func(t *testing.T) {
+		gm := GitHubMessage{routes: routes}
+		json, err := os.ReadFile("./testdata/sponsorship.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "sponsorship"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+		assert.Equal(gm.Route(), "sponsor")
+	})
+
+	t.Run("other event", func(t *testing.T) {
+		gm := GitHubMessage{routes: routes}
+		json, err := os.ReadFile("./testdata/push.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = gm.Init(
+			map[string]string{EventHeader: "push"},
+			(*string)(unsafe.Pointer(&json)),
+		)
+		assert.Nil(err)
+		assert.Equal(gm.Route(), "")
+	})
+
+}
+
+func TestGitHubMessageLocale(t *testing.T) {
+	assert := assert.New(t)
+	i18n.Configure("", map[string]string{"audit": "en"})
+
+	t.Run("route locale", func(t *testing.T) {
+		gm := GitHubMessage{routes: config.Routes{Audit: "audit"}}
+		json, err := os.ReadFile("./testdata/member.json")
+		if err != nil {
+			t.Error(err)
+		}
+		body := string(json)
+		err = gm.Init(map[string]string{EventHeader: "member"}, &body)
+		assert.Nil(err)
+
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.InsertField("Account", "Codertocat", true)
+		a.InsertField("Action", "Collaborator permission changed", true)
+		a.InsertField("User", "Octocat")
+		a.InsertField("Permission (before)", "write", true)
+		a.InsertField("Permission (after)", "admin", true)
+		a.InsertField("Link", "https://github.com/Octocoders/Hello-World")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("default locale", func(t *testing.T) {
+		gm := GitHubMessage{}
+		buf, err := gm.ToDummyPayload()
+		assert.Nil(err)
+		assert.Contains(buf.String(), "アカウント")
+	})
+
+	t.Cleanup(func(){
+		i18n.Configure("", nil)
+	})
+}
+
+func TestGitHubMessageToDummyPayload(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	gm := GitHubMessage{}
+	buf, err := gm.ToDummyPayload()
+	assert.Nil(err)
+	assert.IsType(buf, &bytes.Buffer{})
+
+	a := builder.NewAttachment()
+	a.InsertField("アカウント", "Bot", true)
+	a.InsertField("アクション", "Invoke", true)
+	a.InsertField("内容", "OK")
+	ebuf, err := a.Build()
+	if err != nil {
+		t.Error(err)
+	}
+
+	assert.Equal(buf, ebuf)
+}
+
+func TestGitHubMessageRepoConfig(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	json, err := os.ReadFile("./testdata/issues.json")
+	if err != nil {
+		t.Error(err)
+	}
+	body := string(json)
+	key := "repo_config:Codertocat/Hello-World:.github/ggnb.yml@aaa"
+	shaKey := "repo_config_sha:Codertocat/Hello-World:master"
+	repoFile := []byte("route: frontend\nfilter:\n  rules:\n    - exclude event=issues action=edited\n")
+
+	newMessage := func(c *api.MockedClient, s *store.MockedStore, allow ...string) *GitHubMessage {
+		gm := &GitHubMessage{
+			api: c,
+			store: s,
+			slackRoutes: map[string]bool{"frontend": true},
+			repoConfig: config.Repo{Path: ".github/ggnb.yml", Allow: allow},
+		}
+		assert.Nil(gm.Init(map[string]string{EventHeader: "issues"}, &body))
+		return gm
+	}
+
+	t.Run("route", func(t *testing.T) {
+		c := &api.MockedClient{}
+		c.On("GetBranchSHA", "Codertocat", "Hello-World", "master").Return("aaa", nil)
+		c.On("GetFile", "Codertocat", "Hello-World", ".github/ggnb.yml", "aaa").Return(repoFile, nil)
+		s := &store.MockedStore{}
+		s.On("Get", shaKey).Return(nil, nil)
+		s.On("Set", shaKey, mock.Anything).Return(nil)
+		s.On("Get", key).Return(nil, nil)
+		s.On("Set", key, repoFile).Return(nil)
+
+		gm := newMessage(c, s, config.RepoRoute)
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		// filter は許可されていないため除外しない
+		assert.NotNil(buf)
+		assert.Equal(gm.Route(), "frontend")
+		c.AssertExpectations(t)
+		s.AssertExpectations(t)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		c := &api.MockedClient{}
+		c.On("GetBranchSHA", "Codertocat", "Hello-World", "master").Return("aaa", nil)
+		// キャッシュされている場合は取得しない
+		s := &store.MockedStore{}
+		s.On("Get", shaKey).Return(nil, nil)
+		s.On("Set", shaKey, mock.Anything).Return(nil)
+		s.On("Get", key).Return(repoFile, nil)
+
+		gm := newMessage(c, s, config.RepoFilter)
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Nil(buf)
+		assert.Equal(gm.Route(), "")
+		c.AssertNotCalled(t, "GetFile", "Codertocat", "Hello-World", ".github/ggnb.yml", "aaa")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		c := &api.MockedClient{}
+		c.On("GetBranchSHA", "Codertocat", "Hello-World", "master").Return("aaa", nil)
+		c.On("GetFile", "Codertocat", "Hello-World", ".github/ggnb.yml", "aaa").Return(nil, nil)
+		s := &store.MockedStore{}
+		s.On("Get", shaKey).Return(nil, nil)
+		s.On("Set", shaKey, mock.Anything).Return(nil)
+		s.On("Get", key).Return(nil, nil)
+		s.On("Set", key, []byte{}).Return(nil)
+
+		gm := newMessage(c, s, config.RepoRoute, config.RepoFilter)
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.NotNil(buf)
+		assert.Nil(gm.repo)
+		s.AssertExpectations(t)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		c := &api.MockedClient{}
+		c.On("GetBranchSHA", "Codertocat", "Hello-World", "master").Return("", errors.New("mocked"))
+
+		s := &store.MockedStore{}
+		s.On("Get", shaKey).Return(nil, nil)
+
+		gm := newMessage(c, s, config.RepoRoute)
+		buf, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.NotNil(buf)
+		assert.Equal(gm.Route(), "")
+	})
+
+	t.Run("unknown route", func(t *testing.T) {
+		c := &api.MockedClient{}
+		c.On("GetBranchSHA", "Codertocat", "Hello-World", "master").Return("aaa", nil)
+		s := &store.MockedStore{}
+		s.On("Get", shaKey).Return(nil, nil)
+		s.On("Set", shaKey, mock.Anything).Return(nil)
+		s.On("Get", key).Return([]byte("route: backend\n"), nil)
+
+		gm := newMessage(c, s, config.RepoRoute)
+		_, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Equal(gm.Route(), "")
+	})
+
+	t.Run("cached sha", func(t *testing.T) {
+		c := &api.MockedClient{}
+		s := &store.MockedStore{}
+		s.On("Get", shaKey).Return([]byte("aaa "+time.Now().Format(time.RFC3339)), nil)
+		s.On("Get", key).Return([]byte("route: frontend\n"), nil)
+
+		gm := newMessage(c, s, config.RepoRoute)
+		_, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Equal(gm.Route(), "frontend")
+		c.AssertNotCalled(t, "GetBranchSHA", "Codertocat", "Hello-World", "master")
+	})
+
+	t.Run("expired sha", func(t *testing.T) {
+		c := &api.MockedClient{}
+		c.On("GetBranchSHA", "Codertocat", "Hello-World", "master").Return("aaa", nil)
+		s := &store.MockedStore{}
+		s.On("Get", shaKey).Return([]byte("zzz "+time.Now().Add(-repoConfigSHATTL).Format(time.RFC3339)), nil)
+		s.On("Set", shaKey, mock.Anything).Return(nil)
+		s.On("Get", key).Return([]byte("route: frontend\n"), nil)
+
+		gm := newMessage(c, s, config.RepoRoute)
+		_, err := gm.ToPayload()
+		assert.Nil(err)
+		assert.Equal(gm.Route(), "frontend")
+		c.AssertExpectations(t)
+	})
+}
+
+func TestGitHubMessageUrgent(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	json, err := os.ReadFile("./testdata/issues.json")
+	if err != nil {
+		t.Error(err)
+	}
+	issues := string(json)
+	rule, err := filter.ParseRule("include event=issues sender=Codertocat")
+	assert.Nil(err)
+
+	for _, tc := range []struct {
+		name string
+		eventType string
+		body string
+		rules []*filter.Rule
+		expected bool
+	}{
+		{
+			name: "failed production deploy",
+			eventType: "deployment_status",
+			body: `{"action":"created","deployment_status":{"state":"failure","environment":"production"}}`,
+			expected: true,
+		},
+		{
+			name: "failed staging deploy",
+			eventType: "deployment_status",
+			body: `{"action":"created","deployment_status":{"state":"failure","environment":"staging"}}`,
+			expected: false,
+		},
+		{
+			name: "security alert",
+			eventType: "code_scanning_alert",
+			body: `{"action":"created","alert":{"number":1}}`,
+			expected: true,
+		},
+		{
+			name: "fixed security alert",
+			eventType: "code_scanning_alert",
+			body: `{"action":"fixed","alert":{"number":1}}`,
+			expected: false,
+		},
+		{
+			name: "issues",
+			eventType: "issues",
+			body: issues,
+			expected: false,
+		},
+		{
+			name: "urgent rules",
+			eventType: "issues",
+			body: issues,
+			rules: []*filter.Rule{rule},
+			expected: true,
+		},
+	} {
+		// go-github が対応していない Event は汎用の形式で扱う
+		gm := GitHubMessage{renderUnhandled: true, urgentRules: tc.rules}
+		body := tc.body
+		assert.Nil(gm.Init(map[string]string{EventHeader: tc.eventType}, &body), tc.name)
+		assert.Equal(gm.Urgent(), tc.expected, tc.name)
+	}
+}
+
+func TestGitHubMessageDigest(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	json, err := os.ReadFile("./testdata/pull_request.json")
+	if err != nil {
+		t.Error(err)
+	}
+	opened := string(json)
+	merged := strings.Replace(opened, `"action": "opened"`, `"action": "closed"`, 1)
+	merged = strings.Replace(merged, `"merged": false`, `"merged": true`, 1)
+
+	for _, tc := range []struct {
+		name string
+		eventType string
+		body string
+		expected *builder.DigestEntry
+	}{
+		{
+			name: "pull request",
+			eventType: "pull_request",
+			body: opened,
+			expected: &builder.DigestEntry{
+				Event: "pull_request",
+				Action: "opened",
+				Title: "Update the README with new information.",
+				URL: "https://github.com/Codertocat/Hello-World/pull/2",
+				Sender: "Codertocat",
+				SenderType: "User",
+			},
+		},
+		{
+			name: "merged pull request",
+			eventType: "pull_request",
+			body: merged,
+			expected: &builder.DigestEntry{
+				Event: "pull_request",
+				Action: "merged",
+				Title: "Update the README with new information.",
+				URL: "https://github.com/Codertocat/Hello-World/pull/2",
+				Sender: "Codertocat",
+				SenderType: "User",
+			},
+		},
+		{
+			name: "release without name",
+			eventType: "release",
+			body: `{"action":"published","release":{"tag_name":"v1.0.0","html_url":"https://github.com/Codertocat/Hello-World/releases/tag/v1.0.0"},"sender":{"login":"Codertocat","type":"User"}}`,
+			expected: &builder.DigestEntry{
+				Event: "release",
+				Action: "published",
+				Title: "v1.0.0",
+				URL: "https://github.com/Codertocat/Hello-World/releases/tag/v1.0.0",
+				Sender: "Codertocat",
+				SenderType: "User",
+			},
+		},
+	} {
+		gm := GitHubMessage{renderUnhandled: true}
+		body := tc.body
+		assert.Nil(gm.Init(map[string]string{EventHeader: tc.eventType}, &body), tc.name)
+		assert.Equal(gm.Digest(), tc.expected, tc.name)
+	}
 }
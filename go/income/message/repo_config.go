@@ -0,0 +1,164 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SongCastle/ggnb/config"
+	"github.com/SongCastle/ggnb/income/filter"
+)
+
+// デフォルトブランチの SHA をキャッシュする期間 (設定ファイルの変更はこの期間内に反映する)
+const repoConfigSHATTL = 5 * time.Minute
+
+// Event のレポジトリの設定ファイルで上書きした設定
+type repoSettings struct {
+	// 空の場合はデプロイ時の設定に従う
+	route string
+	filter filter.AbstractFilter
+	mentions map[string]string
+}
+
+// 読み込みに失敗した場合はログに出力し、デプロイ時の設定で通知する
+func (gm *GitHubMessage) loadRepoSettings() {
+	gm.repo = nil
+	if gm.repoConfig.Path == "" || gm.api == nil {
+		return
+	}
+	data, err := eventData(gm.event)
+	if err != nil {
+		return
+	}
+	f, err := gm.fetchRepoFile(data)
+	if err != nil {
+		fmt.Printf("Repo Config Failed: %v\n", err)
+		return
+	}
+	if f == nil {
+		return
+	}
+	if dropped := f.Restrict(gm.repoConfig.Allow); len(dropped) > 0 {
+		fmt.Printf(
+			"Repo Config: %s is not allowed to override %s\n",
+			jsonPathString(data, "repository.full_name"), strings.Join(dropped, ", "),
+		)
+	}
+	gm.repo = gm.newRepoSettings(f)
+}
+
+// Pull Request 等で書き換えられないよう、デフォルトブランチの設定ファイルを読み込む
+// 設定ファイルが存在しない場合は nil を返す
+func (gm *GitHubMessage) fetchRepoFile(data map[string]interface{}) (*config.RepoFile, error) {
+	fullName := jsonPathString(data, "repository.full_name")
+	branch := jsonPathString(data, "repository.default_branch")
+	names := strings.SplitN(fullName, "/", 2)
+	if len(names) != 2 || branch == "" {
+		return nil, nil
+	}
+	owner, repo := names[0], names[1]
+
+	sha, err := gm.branchSHA(data, owner, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	// SHA ごとにキャッシュする (設定ファイルが存在しない場合は空)
+	key := fmt.Sprintf("repo_config:%s:%s@%s", fullName, gm.repoConfig.Path, sha)
+	text, err := gm.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if text == nil {
+		if text, err = gm.api.GetFile(owner, repo, gm.repoConfig.Path, sha); err != nil {
+			return nil, err
+		}
+		if text == nil {
+			text = []byte{}
+		}
+		if err := gm.store.Set(key, text); err != nil {
+			return nil, err
+		}
+	}
+	if len(text) == 0 {
+		return nil, nil
+	}
+	f, err := config.ParseRepoFile(text)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Invalid %s (%s@%s): %v", gm.repoConfig.Path, fullName, shortSHA(sha), err))
+	}
+	return f, nil
+}
+
+// デフォルトブランチの SHA は repoConfigSHATTL の間キャッシュする (Event ごとに API を呼ばないため)
+// デフォルトブランチへの push の場合は API を呼ばずに済む
+func (gm *GitHubMessage) branchSHA(data map[string]interface{}, owner, repo, branch string) (string, error) {
+	key := fmt.Sprintf("repo_config_sha:%s/%s:%s", owner, repo, branch)
+	now := time.Now()
+	if gm.eventType == "push" && jsonPathString(data, "ref") == "refs/heads/"+branch && data["deleted"] != true {
+		if sha := jsonPathString(data, "after"); sha != "" {
+			return sha, gm.store.Set(key, []byte(sha+" "+now.Format(time.RFC3339)))
+		}
+	}
+	value, err := gm.store.Get(key)
+	if err != nil {
+		return "", err
+	}
+	if fields := strings.Fields(string(value)); len(fields) == 2 {
+		if at, err := time.Parse(time.RFC3339, fields[1]); err == nil && now.Sub(at) < repoConfigSHATTL {
+			return fields[0], nil
+		}
+	}
+	sha, err := gm.api.GetBranchSHA(owner, repo, branch)
+	if err != nil {
+		return "", err
+	}
+	return sha, gm.store.Set(key, []byte(sha+" "+now.Format(time.RFC3339)))
+}
+
+func (gm *GitHubMessage) newRepoSettings(f *config.RepoFile) *repoSettings {
+	s := &repoSettings{}
+	if f.Route != "" {
+		// WebHook URL はデプロイ時に設定されたもののみ利用できる
+		if gm.slackRoutes[f.Route] {
+			s.route = f.Route
+		} else {
+			fmt.Printf("Repo Config: route %q is not configured\n", f.Route)
+		}
+	}
+	if len(f.Filter.Rules) > 0 {
+		// レポジトリのルールを先に評価する
+		rules := append(append([]string{}, f.Filter.Rules...), gm.filterRules...)
+		// 不正なルールの場合はデプロイ時のルールのみで判定する
+		if rf, err := filter.NewFilter(rules, gm.filterDryRun); err != nil {
+			fmt.Printf("Repo Config: filter rules are ignored: %v\n", err)
+		} else {
+			s.filter = rf
+		}
+	}
+	if len(f.Mentions) > 0 {
+		s.mentions = map[string]string{}
+		for key, id := range gm.mentions {
+			s.mentions[key] = id
+		}
+		for key, id := range normalizeMentions(f.Mentions) {
+			s.mentions[key] = id
+		}
+	}
+	return s
+}
+
+func (gm *GitHubMessage) activeFilter() filter.AbstractFilter {
+	if gm.repo != nil && gm.repo.filter != nil {
+		return gm.repo.filter
+	}
+	return gm.filter
+}
+
+func (gm *GitHubMessage) activeMentions() map[string]string {
+	if gm.repo != nil && gm.repo.mentions != nil {
+		return gm.repo.mentions
+	}
+	return gm.mentions
+}
@@ -0,0 +1,211 @@
+package template
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/SongCastle/ggnb/income/builder"
+	"github.com/SongCastle/ggnb/income/i18n"
+)
+
+const (
+	Ext = ".tmpl"
+)
+
+// <event>.tmpl もしくは <event>.<action>.tmpl
+var templateName = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)?$`)
+
+// dir が空の場合は nil を返す
+func NewTemplates(dir string) (AbstractTemplates, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	t := &FileTemplates{}
+	if err := t.Init(dir); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+type AbstractTemplates interface {
+	Init(dir string) error
+	Has(eventType, action string) bool
+	Render(l *i18n.Localizer, eventType, action string, data interface{}, mention func(string) string) (*bytes.Buffer, error)
+}
+
+type FileTemplates struct {
+	templates map[string]*template.Template
+}
+
+// テンプレートを読み込み、空のデータで実行できることを確認する
+func (ft *FileTemplates) Init(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	if err != nil {
+		return err
+	}
+	ft.templates = map[string]*template.Template{}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), Ext)
+		if !templateName.MatchString(name) {
+			return errors.New(fmt.Sprintf("Invalid template name: %s", filepath.Base(path)))
+		}
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		t, err := template.New(name).Funcs(newRenderer(nil).funcs()).Parse(string(text))
+		if err != nil {
+			return err
+		}
+		if _, err := render(i18n.NewLocalizer(i18n.DefaultLocale), t, map[string]interface{}{}, nil); err != nil {
+			return err
+		}
+		ft.templates[name] = t
+	}
+	return nil
+}
+
+func (ft *FileTemplates) Has(eventType, action string) bool {
+	return ft.lookup(eventType, action) != nil
+}
+
+// 通知しない場合 (何も出力しないテンプレート) は nil を返す
+func (ft *FileTemplates) Render(l *i18n.Localizer, eventType, action string, data interface{}, mention func(string) string) (*bytes.Buffer, error) {
+	t := ft.lookup(eventType, action)
+	if t == nil {
+		return nil, errors.New(fmt.Sprintf("template not found: %s", eventType))
+	}
+	return render(l, t, data, mention)
+}
+
+// action ごとのテンプレートを優先する
+func (ft *FileTemplates) lookup(eventType, action string) *template.Template {
+	if action != "" {
+		if t, ok := ft.templates[eventType+"."+action]; ok {
+			return t
+		}
+	}
+	return ft.templates[eventType]
+}
+
+func render(l *i18n.Localizer, t *template.Template, data interface{}, mention func(string) string) (*bytes.Buffer, error) {
+	r := newRenderer(mention)
+	t, err := t.Clone()
+	if err != nil {
+		return nil, err
+	}
+	var out strings.Builder
+	if err := t.Funcs(r.funcs()).Execute(&out, data); err != nil {
+		return nil, err
+	}
+	// field 以外の出力は「内容」として扱う
+	r.field(l.T("field.body"), strings.TrimSpace(out.String()))
+	if len(r.fields) == 0 {
+		return nil, nil
+	}
+	a := builder.NewAttachment()
+	if r.color != "" {
+		a.SetColor(r.color)
+	}
+	for _, f := range r.fields {
+		a.InsertField(f.title, f.value, f.short)
+	}
+	return a.Build()
+}
+
+type renderedField struct {
+	title string
+	value string
+	short bool
+}
+
+// テンプレートの実行ごとに field と color を集める
+type renderer struct {
+	fields []renderedField
+	color string
+	mention func(string) string
+}
+
+func newRenderer(mention func(string) string) *renderer {
+	return &renderer{mention: mention}
+}
+
+func (r *renderer) funcs() template.FuncMap {
+	return template.FuncMap{
+		"field": func(title string, value interface{}, short ...bool) string {
+			r.field(title, toString(value), short...)
+			return ""
+		},
+		"color": func(color string) string {
+			r.color = color
+			return ""
+		},
+		"truncate": truncate,
+		"mention": func(login interface{}) string {
+			s := toString(login)
+			if r.mention == nil || s == "" {
+				return s
+			}
+			return r.mention(s)
+		},
+		"link": link,
+		"escape": func(v interface{}) string {
+			return escape(toString(v))
+		},
+	}
+}
+
+func (r *renderer) field(title, value string, short ...bool) {
+	if title == "" || value == "" {
+		return
+	}
+	s := len(short) > 0 && short[0]
+	r.fields = append(r.fields, renderedField{title: title, value: value, short: s})
+}
+
+// JSON の値を文字列にする (存在しない場合は空文字)
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// n 文字を超える場合は切り詰めて … を付ける
+func truncate(n int, v interface{}) string {
+	s := toString(v)
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "…"
+}
+
+func link(url, text interface{}) string {
+	u, t := toString(url), escape(toString(text))
+	if u == "" {
+		return t
+	}
+	if t == "" {
+		return fmt.Sprintf("<%s>", u)
+	}
+	return fmt.Sprintf("<%s|%s>", u, t)
+}
+
+// Slack の mrkdwn で制御文字として扱われる文字をエスケープする
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,28 @@
+package template
+
+import (
+	"bytes"
+
+	"github.com/SongCastle/ggnb/income/i18n"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockedTemplates struct {
+	mock.Mock
+}
+
+func (m *MockedTemplates) Init(dir string) error {
+	args := m.Called(dir)
+	return args.Error(0)
+}
+
+func (m *MockedTemplates) Has(eventType, action string) bool {
+	args := m.Called(eventType, action)
+	return args.Bool(0)
+}
+
+func (m *MockedTemplates) Render(l *i18n.Localizer, eventType, action string, data interface{}, mention func(string) string) (*bytes.Buffer, error) {
+	args := m.Called(l, eventType, action, data, mention)
+	buf, _ := args[0].(*bytes.Buffer)
+	return buf, args.Error(1)
+}
@@ -0,0 +1,146 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SongCastle/ggnb/income/builder"
+	"github.com/SongCastle/ggnb/income/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTemplates(t *testing.T, templates map[string]string) string {
+	dir := t.TempDir()
+	for name, text := range templates {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestNewTemplates(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("without dir", func(t *testing.T) {
+		tmpl, err := NewTemplates("")
+		assert.Nil(err)
+		assert.Nil(tmpl)
+	})
+
+	t.Run("with dir", func(t *testing.T) {
+		tmpl, err := NewTemplates(writeTemplates(t, map[string]string{"issues.tmpl": "{{.action}}"}))
+		assert.Nil(err)
+		assert.IsType(tmpl, &FileTemplates{})
+	})
+}
+
+func TestFileTemplatesInit(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	t.Run("invalid syntax", func(t *testing.T) {
+		ft := &FileTemplates{}
+		err := ft.Init(writeTemplates(t, map[string]string{"issues.tmpl": "{{.action"}))
+		assert.NotNil(err)
+	})
+
+	t.Run("undefined function", func(t *testing.T) {
+		ft := &FileTemplates{}
+		err := ft.Init(writeTemplates(t, map[string]string{"issues.tmpl": `{{upper .action}}`}))
+		assert.NotNil(err)
+	})
+
+	t.Run("execution error", func(t *testing.T) {
+		ft := &FileTemplates{}
+		err := ft.Init(writeTemplates(t, map[string]string{"issues.tmpl": `{{truncate "x" .action}}`}))
+		assert.NotNil(err)
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		ft := &FileTemplates{}
+		err := ft.Init(writeTemplates(t, map[string]string{"Issues Opened.tmpl": "{{.action}}"}))
+		assert.EqualError(err, "Invalid template name: Issues Opened.tmpl")
+	})
+}
+
+func TestFileTemplatesRender(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	ft := &FileTemplates{}
+	err := ft.Init(writeTemplates(t, map[string]string{
+		"issues.tmpl": `{{field "アクション" .action true}}`,
+		"issues.opened.tmpl": strings.Join([]string{
+			`{{color "#36c5f0"}}`,
+			`{{field "アカウント" (mention .sender.login) true}}`,
+			`{{field "番号" .issue.number true}}`,
+			`{{field "タイトル" (link .issue.html_url (truncate 5 .issue.title))}}`,
+			`{{escape .issue.body}}`,
+		}, ""),
+		"issues.deleted.tmpl": `{{/* 通知しない */}}`,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]interface{}{
+		"action": "opened",
+		"issue": map[string]interface{}{
+			"number": float64(1),
+			"title": "Spelling error in the README file",
+			"html_url": "https://github.com/Codertocat/Hello-World/issues/1",
+			"body": "<b>typo</b>",
+		},
+		"sender": map[string]interface{}{"login": "Codertocat"},
+	}
+	ja := i18n.NewLocalizer("ja")
+	mention := func(login string) string {
+		return "<@U0123>"
+	}
+
+	t.Run("action template", func(t *testing.T) {
+		assert.True(ft.Has("issues", "opened"))
+		buf, err := ft.Render(ja, "issues", "opened", data, mention)
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.SetColor("#36c5f0")
+		a.InsertField("アカウント", "<@U0123>", true)
+		a.InsertField("番号", "1", true)
+		a.InsertField("タイトル", "<https://github.com/Codertocat/Hello-World/issues/1|Spell…>")
+		a.InsertField("内容", "&lt;b&gt;typo&lt;/b&gt;")
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("event template", func(t *testing.T) {
+		assert.True(ft.Has("issues", "closed"))
+		buf, err := ft.Render(ja, "issues", "closed", map[string]interface{}{"action": "closed"}, nil)
+		assert.Nil(err)
+
+		a := builder.NewAttachment()
+		a.InsertField("アクション", "closed", true)
+		ebuf, err := a.Build()
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(buf, ebuf)
+	})
+
+	t.Run("empty output", func(t *testing.T) {
+		buf, err := ft.Render(ja, "issues", "deleted", data, nil)
+		assert.Nil(err)
+		assert.Nil(buf)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		assert.False(ft.Has("push", ""))
+		_, err := ft.Render(ja, "push", "", data, nil)
+		assert.NotNil(err)
+	})
+}
@@ -3,25 +3,59 @@ package main
 import (
 	"fmt"
 
+	"github.com/SongCastle/ggnb/config"
+	"github.com/SongCastle/ggnb/income"
+	"github.com/SongCastle/ggnb/income/i18n"
 	"github.com/SongCastle/ggnb/income/message"
+	"github.com/SongCastle/ggnb/outcome"
 	"github.com/SongCastle/ggnb/outcome/client"
+	"github.com/SongCastle/ggnb/outcome/digest"
+	"github.com/SongCastle/ggnb/outcome/schedule"
 	"github.com/SongCastle/ggnb/handler"
+
+	// Lambda のランタイムにはタイムゾーンのデータが含まれない
+	_ "time/tzdata"
 )
 
-func main() {
+// 設定ファイルを読み込み直した場合にも利用する
+func build(cfg *config.Config) (income.AbstractManager, outcome.AbstractManager, error) {
 	// Create Message (income)
-	m, err := message.NewMessage()
+	m, err := message.NewMessage(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Create Client (outcome)
+	c, err := client.NewClient(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := schedule.NewSchedule(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	d, err := digest.NewDigest(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	i18n.Configure(cfg.Locale.Default, cfg.Locale.Routes)
+	// Create Manager
+	return income.NewManager(m), outcome.NewManager(c, s, d), nil
+}
+
+func main() {
+	// Load Config
+	cfg, err := config.Load()
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return
 	}
-	// Create Client (outcome)
-	c := client.NewClient()
-	if err := c.Init(); err != nil {
+	in, out, err := build(cfg)
+	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return
 	}
 	// Create & Start Handler
-	h := handler.New(m, c)
+	h := handler.New(cfg, in, out)
+	h.SetReloader(handler.NewReloader(cfg, build))
 	h.Start()
 }
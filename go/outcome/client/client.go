@@ -6,54 +6,80 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-)
+	"strings"
 
-const WebHookUrl = "SLACK_WEBHOOK_URL"
+	"github.com/SongCastle/ggnb/config"
+)
 
-func NewClient() AbstractClient {
+func NewClient(cfg *config.Config) (AbstractClient, error) {
 	// Only Slack
-	return &SlackClient{}
+	c := &SlackClient{}
+	if err := c.Init(cfg.Slack.WebHookUrl, cfg.Slack.Routes); err != nil {
+		return nil, err
+	}
+	return c, nil
 }
 
 type AbstractClient interface {
-	Init() error
+	Init(webHookUrl string, routeWebHookUrls map[string]string) error
 	Post(buff *bytes.Buffer) ([]byte, error)
+	PostTo(route string, buff *bytes.Buffer) ([]byte, error)
 }
 
 type SlackClient struct {
 	webHookUrl string
+	routeWebHookUrls map[string]string
 }
 
-func (sc *SlackClient) Init() error {
-	sc.webHookUrl = os.Getenv(WebHookUrl)
+// route ごとの WebHook URL は route (小文字) から URL
+func (sc *SlackClient) Init(webHookUrl string, routeWebHookUrls map[string]string) error {
+	sc.webHookUrl = webHookUrl
 	if sc.webHookUrl == "" {
 		return errors.New("WebhookUrl is brank")
 	}
+	sc.routeWebHookUrls = map[string]string{}
+	for route, url := range routeWebHookUrls {
+		if url != "" {
+			sc.routeWebHookUrls[strings.ToLower(route)] = url
+		}
+	}
 	return nil
 }
 
 func (sc *SlackClient) Post(msg *bytes.Buffer) ([]byte, error) {
-	body, err := sc.request(msg)
+	return sc.PostTo("", msg)
+}
+
+// route が空 (もしくは default) の場合は、デフォルトの WebHook URL へ送信する
+// 未設定の route は、別の通知先へ送らないようにエラーを返す
+func (sc *SlackClient) PostTo(route string, msg *bytes.Buffer) ([]byte, error) {
+	url := sc.webHookUrl
+	if route := strings.ToLower(route); route != "" && route != config.DefaultDestination {
+		var ok bool
+		if url, ok = sc.routeWebHookUrls[route]; !ok {
+			return nil, errors.New(fmt.Sprintf("WebhookUrl is not set for route: %s", route))
+		}
+	}
+	body, err := sc.request(url, msg)
 	if err != nil {
 		return nil, err
 	}
 	return body, nil
 }
 
-func (sc *SlackClient) request(msg *bytes.Buffer) ([]byte, error) {
-	req, err := http.NewRequest("POST", sc.webHookUrl, msg)
-	req.Header.Add("Content-Type", "application/json")
+func (sc *SlackClient) request(url string, msg *bytes.Buffer) ([]byte, error) {
+	req, err := http.NewRequest("POST", url, msg)
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Add("Content-Type", "application/json")
 
 	c := &http.Client{}
 	resp, err := c.Do(req)
-	defer resp.Body.Close()
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -10,8 +10,8 @@ type MockedClient struct {
 	mock.Mock
 }
 
-func (m *MockedClient) Init() error {
-	args := m.Called()
+func (m *MockedClient) Init(webHookUrl string, routeWebHookUrls map[string]string) error {
+	args := m.Called(webHookUrl, routeWebHookUrls)
 	return args.Error(0)
 }
 
@@ -19,3 +19,8 @@ func (m *MockedClient) Post(msg *bytes.Buffer) ([]byte, error) {
 	args := m.Called(msg)
 	return args[0].([]byte), args.Error(1)
 }
+
+func (m *MockedClient) PostTo(route string, msg *bytes.Buffer) ([]byte, error) {
+	args := m.Called(route, msg)
+	return args[0].([]byte), args.Error(1)
+}
@@ -4,50 +4,51 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
-	"os"
 	"testing"
 
+	"github.com/SongCastle/ggnb/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/jarcoal/httpmock"
 )
 
 func TestNewClient(t *testing.T) {
+	assert := assert.New(t)
+
 	// Only Slack
 	t.Run("Slack", func(t *testing.T) {
-		c := NewClient()
-		_, ok := c.(AbstractClient)
-		assert.True(t, ok)
+		c, err := NewClient(&config.Config{Slack: config.Slack{WebHookUrl: "https://example.com"}})
+		assert.Nil(err)
+		assert.IsType(c, &SlackClient{})
+	})
+
+	t.Run("without WebHookUrl", func(t *testing.T) {
+		_, err := NewClient(&config.Config{})
+		assert.EqualError(err, "WebhookUrl is brank")
 	})
 }
 
 func TestSlackClientInit(t *testing.T) {
 	assert := assert.New(t)
-	beforeWebHookUrl := os.Getenv(WebHookUrl)
 
 	sc := &SlackClient{}
 
 	t.Run("without WebHookUrl", func(t *testing.T) {
-		if err := os.Unsetenv(WebHookUrl); err != nil {
-			t.Fatal(err)
-		}
-		err := sc.Init()
+		err := sc.Init("", nil)
 		assert.EqualError(err, "WebhookUrl is brank")
 	})
 
 	t.Run("with WebHookUrl", func(t *testing.T) {
 		mockUrl := "https://example.com"
-		if err := os.Setenv(WebHookUrl, mockUrl); err != nil {
-			t.Fatal(err)
-		}
-		err := sc.Init()
+		err := sc.Init(mockUrl, nil)
 		assert.Nil(err)
 		assert.Equal(sc.webHookUrl, mockUrl)
 	})
 
-	t.Cleanup(func(){
-		if err := os.Setenv(WebHookUrl, beforeWebHookUrl); err != nil {
-			t.Fatal(err)
-		}
+	t.Run("with route WebHookUrl", func(t *testing.T) {
+		mockUrl, mockAuditUrl := "https://example.com", "https://example.com/audit"
+		err := sc.Init(mockUrl, map[string]string{"AUDIT": mockAuditUrl, "sponsor": ""})
+		assert.Nil(err)
+		assert.Equal(sc.routeWebHookUrls, map[string]string{"audit": mockAuditUrl})
 	})
 }
 
@@ -91,3 +92,61 @@ func TestSlackClientPost(t *testing.T) {
 		})
 	})
 }
+
+func TestSlackClientPostTo(t *testing.T) {
+	assert := assert.New(t)
+
+	mockUrl, mockAuditUrl := "https://example.com", "https://example.com/audit"
+	msg := bytes.NewBufferString(`{"body": "test"}`)
+
+	sc := &SlackClient{
+		webHookUrl: mockUrl,
+		routeWebHookUrls: map[string]string{"audit": mockAuditUrl},
+	}
+
+	t.Run("configured route", func(t *testing.T) {
+		httpmock.RegisterResponder("POST", mockAuditUrl,
+			httpmock.NewStringResponder(200, "audit"))
+		httpmock.Activate()
+
+		body, err := sc.PostTo("audit", msg)
+		assert.Nil(err)
+		assert.Equal(body, []byte("audit"))
+
+		t.Cleanup(func(){
+			httpmock.DeactivateAndReset()
+		})
+	})
+
+	t.Run("default route", func(t *testing.T) {
+		httpmock.RegisterResponder("POST", mockUrl,
+			httpmock.NewStringResponder(200, "ok"))
+		httpmock.Activate()
+
+		body, err := sc.PostTo("default", msg)
+		assert.Nil(err)
+		assert.Equal(body, []byte("ok"))
+
+		t.Cleanup(func(){
+			httpmock.DeactivateAndReset()
+		})
+	})
+
+	// デフォルトの WebHook URL へは送信しない
+	t.Run("unknown route", func(t *testing.T) {
+		httpmock.RegisterResponder("POST", mockUrl,
+			httpmock.NewStringResponder(200, "ok"))
+		httpmock.Activate()
+
+		body, err := sc.PostTo("unknown", msg)
+		assert.Nil(body)
+		if assert.NotNil(err) {
+			assert.Equal(err.Error(), "WebhookUrl is not set for route: unknown")
+		}
+		assert.Equal(httpmock.GetTotalCallCount(), 0)
+
+		t.Cleanup(func(){
+			httpmock.DeactivateAndReset()
+		})
+	})
+}
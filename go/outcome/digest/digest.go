@@ -0,0 +1,226 @@
+package digest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SongCastle/ggnb/config"
+	"github.com/SongCastle/ggnb/cron"
+	"github.com/SongCastle/ggnb/income/builder"
+	"github.com/SongCastle/ggnb/store"
+)
+
+const (
+	entriesKeyPrefix = "digest_entries:"
+	// 集計を始めた時刻
+	sinceKeyPrefix = "digest_since:"
+	// 集計中の route
+	routesKey = "digest_routes"
+)
+
+// digest.routes が設定されていない場合は nil を返す
+// cfg は config.Load で検証済みのもの
+func NewDigest(cfg *config.Config) (AbstractDigest, error) {
+	if len(cfg.Digest.Routes) == 0 {
+		return nil, nil
+	}
+	schedules := map[string]*Schedule{}
+	for route, d := range cfg.Digest.Routes {
+		s, err := ParseSchedule(d)
+		if err != nil {
+			return nil, err
+		}
+		schedules[route] = s
+	}
+	s, err := store.NewStore(cfg.Store.Dir)
+	if err != nil {
+		return nil, err
+	}
+	sd := &StoreDigest{}
+	sd.Init(schedules, s)
+	return sd, nil
+}
+
+type AbstractDigest interface {
+	Init(schedules map[string]*Schedule, s store.AbstractStore)
+	// route へ個別に通知しない場合は true
+	Enabled(route string) bool
+	Add(route string, e *builder.DigestEntry, now time.Time) error
+	// 通知する時刻になった route の概要を取り出す
+	Release(now time.Time) ([]*Summary, error)
+	// 通知できなかった概要を戻す (次回、その後に記録した Event とまとめて通知する)
+	Restore(s *Summary) error
+}
+
+type Summary struct {
+	Route string
+	From time.Time
+	To time.Time
+	Entries []*builder.DigestEntry
+}
+
+type Schedule struct {
+	cron *cron.Schedule
+	location *time.Location
+}
+
+func ParseSchedule(d config.DigestSchedule) (*Schedule, error) {
+	c, err := cron.Parse(d.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(d.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	return &Schedule{cron: c, location: loc}, nil
+}
+
+// since より後に通知する時刻が来ていれば true
+func (s *Schedule) Due(since, now time.Time) bool {
+	next := s.cron.Next(since.In(s.location))
+	return !next.IsZero() && !next.After(now)
+}
+
+// 集計中の Event は store に保存する
+// (Lambda 上ではコンテナが破棄されると失われるため、STORE_DIR は永続化されたディレクトリを推奨)
+type StoreDigest struct {
+	schedules map[string]*Schedule
+	store store.AbstractStore
+}
+
+func (sd *StoreDigest) Init(schedules map[string]*Schedule, s store.AbstractStore) {
+	sd.schedules = schedules
+	sd.store = s
+}
+
+// route ごとの設定がない場合は DefaultDestination の設定に従う
+func (sd *StoreDigest) schedule(route string) *Schedule {
+	if s, ok := sd.schedules[strings.ToLower(route)]; ok && route != "" {
+		return s
+	}
+	return sd.schedules[config.DefaultDestination]
+}
+
+func (sd *StoreDigest) Enabled(route string) bool {
+	return sd.schedule(route) != nil
+}
+
+func (sd *StoreDigest) Add(route string, e *builder.DigestEntry, now time.Time) error {
+	var entries []*builder.DigestEntry
+	if err := sd.load(entriesKeyPrefix+route, &entries); err != nil {
+		return err
+	}
+	if err := sd.save(entriesKeyPrefix+route, append(entries, e)); err != nil {
+		return err
+	}
+	routes, err := sd.routes()
+	if err != nil {
+		return err
+	}
+	for _, r := range routes {
+		if r == route {
+			return nil
+		}
+	}
+	if err := sd.save(sinceKeyPrefix+route, now); err != nil {
+		return err
+	}
+	return sd.save(routesKey, append(routes, route))
+}
+
+func (sd *StoreDigest) Release(now time.Time) ([]*Summary, error) {
+	routes, err := sd.routes()
+	if err != nil {
+		return nil, err
+	}
+	var summaries []*Summary
+	var rest []string
+	for _, route := range routes {
+		var since time.Time
+		if err := sd.load(sinceKeyPrefix+route, &since); err != nil {
+			return nil, err
+		}
+		s := sd.schedule(route)
+		// digest.routes から削除された route は直ちに通知する
+		if s != nil && !s.Due(since, now) {
+			rest = append(rest, route)
+			continue
+		}
+		var entries []*builder.DigestEntry
+		if err := sd.load(entriesKeyPrefix+route, &entries); err != nil {
+			return nil, err
+		}
+		if err := sd.save(entriesKeyPrefix+route, []*builder.DigestEntry{}); err != nil {
+			return nil, err
+		}
+		loc := time.UTC
+		if s != nil {
+			loc = s.location
+		}
+		summaries = append(summaries, &Summary{Route: route, From: since.In(loc), To: now.In(loc), Entries: entries})
+	}
+	if len(summaries) == 0 {
+		return nil, nil
+	}
+	sort.Strings(rest)
+	if err := sd.save(routesKey, rest); err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+func (sd *StoreDigest) Restore(s *Summary) error {
+	var entries []*builder.DigestEntry
+	if err := sd.load(entriesKeyPrefix+s.Route, &entries); err != nil {
+		return err
+	}
+	if err := sd.save(entriesKeyPrefix+s.Route, append(append([]*builder.DigestEntry{}, s.Entries...), entries...)); err != nil {
+		return err
+	}
+	// 戻した概要の開始時刻から集計する
+	if err := sd.save(sinceKeyPrefix+s.Route, s.From); err != nil {
+		return err
+	}
+	routes, err := sd.routes()
+	if err != nil {
+		return err
+	}
+	for _, r := range routes {
+		if r == s.Route {
+			return nil
+		}
+	}
+	routes = append(routes, s.Route)
+	sort.Strings(routes)
+	return sd.save(routesKey, routes)
+}
+
+func (sd *StoreDigest) routes() ([]string, error) {
+	var routes []string
+	err := sd.load(routesKey, &routes)
+	return routes, err
+}
+
+func (sd *StoreDigest) load(key string, v interface{}) error {
+	value, err := sd.store.Get(key)
+	if err != nil || len(value) == 0 {
+		return err
+	}
+	if err := json.Unmarshal(value, v); err != nil {
+		return errors.New(fmt.Sprintf("broken %s: %v", key, err))
+	}
+	return nil
+}
+
+func (sd *StoreDigest) save(key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return sd.store.Set(key, value)
+}
@@ -0,0 +1,37 @@
+package digest
+
+import (
+	"time"
+
+	"github.com/SongCastle/ggnb/income/builder"
+	"github.com/SongCastle/ggnb/store"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockedDigest struct {
+	mock.Mock
+}
+
+func (m *MockedDigest) Init(schedules map[string]*Schedule, s store.AbstractStore) {
+}
+
+func (m *MockedDigest) Enabled(route string) bool {
+	args := m.Called(route)
+	return args.Bool(0)
+}
+
+func (m *MockedDigest) Add(route string, e *builder.DigestEntry, now time.Time) error {
+	args := m.Called(route, e, now)
+	return args.Error(0)
+}
+
+func (m *MockedDigest) Release(now time.Time) ([]*Summary, error) {
+	args := m.Called(now)
+	summaries, _ := args[0].([]*Summary)
+	return summaries, args.Error(1)
+}
+
+func (m *MockedDigest) Restore(s *Summary) error {
+	args := m.Called(s)
+	return args.Error(0)
+}
@@ -0,0 +1,109 @@
+package digest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SongCastle/ggnb/config"
+	"github.com/SongCastle/ggnb/income/builder"
+	"github.com/SongCastle/ggnb/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDigest(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	t.Run("without digest", func(t *testing.T) {
+		d, err := NewDigest(&config.Config{})
+		assert.Nil(err)
+		assert.Nil(d)
+	})
+
+	t.Run("with digest", func(t *testing.T) {
+		cfg := &config.Config{
+			Store: config.Store{Dir: t.TempDir()},
+			Digest: config.Digest{
+				Routes: map[string]config.DigestSchedule{"team": {Schedule: "@daily", Timezone: "Asia/Tokyo"}},
+			},
+		}
+		d, err := NewDigest(cfg)
+		assert.Nil(err)
+		assert.IsType(d, &StoreDigest{})
+	})
+}
+
+func TestScheduleDue(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	jst := time.FixedZone("JST", 9*60*60)
+	s, err := ParseSchedule(config.DigestSchedule{Schedule: "0 9 * * mon-fri", Timezone: "Asia/Tokyo"})
+	assert.Nil(err)
+
+	// 金曜日 10:00 から記録した場合、次は月曜日 9:00
+	since := time.Date(2026, 10, 16, 10, 0, 0, 0, jst)
+	for at, expected := range map[time.Time]bool{
+		time.Date(2026, 10, 17, 9, 0, 0, 0, jst): false,
+		time.Date(2026, 10, 19, 8, 59, 0, 0, jst): false,
+		time.Date(2026, 10, 19, 9, 0, 0, 0, jst): true,
+		// UTC で指定した場合もタイムゾーンで判定する (JST 9:30)
+		time.Date(2026, 10, 19, 0, 30, 0, 0, time.UTC): true,
+	} {
+		assert.Equal(s.Due(since, at), expected, at.String())
+	}
+}
+
+func TestStoreDigest(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	s, err := store.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	daily, err := ParseSchedule(config.DigestSchedule{Schedule: "0 9 * * *"})
+	assert.Nil(err)
+	d := &StoreDigest{}
+	d.Init(map[string]*Schedule{"team": daily}, s)
+
+	assert.True(d.Enabled("Team"))
+	assert.False(d.Enabled(""))
+	assert.False(d.Enabled("audit"))
+
+	since := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	e1 := &builder.DigestEntry{Event: "issues", Action: "opened", Title: "1"}
+	e2 := &builder.DigestEntry{Event: "pull_request", Action: "merged", Title: "2"}
+	assert.Nil(d.Add("team", e1, since))
+	assert.Nil(d.Add("team", e2, since.Add(time.Hour)))
+
+	// 通知する時刻まで取り出さない
+	summaries, err := d.Release(time.Date(2026, 10, 20, 8, 59, 0, 0, time.UTC))
+	assert.Nil(err)
+	assert.Nil(summaries)
+
+	now := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	summaries, err = d.Release(now)
+	assert.Nil(err)
+	assert.Equal(summaries, []*Summary{{Route: "team", From: since, To: now, Entries: []*builder.DigestEntry{e1, e2}}})
+
+	// 取り出した後は空になり、次に記録した時刻から集計する
+	summaries, err = d.Release(now.Add(24 * time.Hour))
+	assert.Nil(err)
+	assert.Nil(summaries)
+	assert.Nil(d.Add("team", e1, now.Add(time.Hour)))
+	summaries, err = d.Release(now.Add(24 * time.Hour))
+	assert.Nil(err)
+	assert.Equal(len(summaries), 1)
+	assert.Equal(summaries[0].From, now.Add(time.Hour))
+	assert.Equal(summaries[0].Entries, []*builder.DigestEntry{e1})
+
+	// 通知できなかった概要は、その後に記録した Event とまとめて取り出す
+	assert.Nil(d.Add("team", e2, now.Add(25*time.Hour)))
+	assert.Nil(d.Restore(summaries[0]))
+	summaries, err = d.Release(now.Add(48 * time.Hour))
+	assert.Nil(err)
+	assert.Equal(len(summaries), 1)
+	assert.Equal(summaries[0].From, now.Add(time.Hour))
+	assert.Equal(summaries[0].Entries, []*builder.DigestEntry{e1, e2})
+}
@@ -3,44 +3,162 @@ package outcome
 import (
 	"bytes"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/SongCastle/ggnb/income/builder"
 	"github.com/SongCastle/ggnb/outcome/client"
+	"github.com/SongCastle/ggnb/outcome/digest"
+	"github.com/SongCastle/ggnb/outcome/schedule"
 )
 
-func NewManager(client client.AbstractClient) AbstractManager {
+// schedule は通知を控える時間帯、digest はダイジェストが設定されていない場合は nil
+func NewManager(client client.AbstractClient, schedule schedule.AbstractSchedule, digest digest.AbstractDigest) AbstractManager {
 	m := &Manager{}
-	m.Init(client)
+	m.Init(client, schedule, digest)
 	return m
 }
 
 type AbstractManager interface {
-	Init(client.AbstractClient)
+	Init(client.AbstractClient, schedule.AbstractSchedule, digest.AbstractDigest)
 	Send(*bytes.Buffer) error
+	SendTo(string, *bytes.Buffer) error
+	Deliver(string, *bytes.Buffer, bool, *builder.DigestEntry) error
+	Flush() error
 	ReportErrorIf(error) error
 }
 
 type Manager struct {
 	client client.AbstractClient
+	schedule schedule.AbstractSchedule
+	digest digest.AbstractDigest
 }
 
-func (m *Manager) Init(client client.AbstractClient) {
+func (m *Manager) Init(client client.AbstractClient, schedule schedule.AbstractSchedule, digest digest.AbstractDigest) {
 	m.client = client
+	m.schedule = schedule
+	m.digest = digest
 }
 
 func (m *Manager) Send(msg *bytes.Buffer) error {
+	return m.SendTo("", msg)
+}
+
+func (m *Manager) SendTo(route string, msg *bytes.Buffer) error {
 	if msg == nil {
 		fmt.Println("Skipped")
 		return nil
 	}
-	fmt.Printf("payload: %s\n", msg.String())
+	fmt.Printf("route: %s, payload: %s\n", route, msg.String())
 
-	if _, err := m.client.Post(msg); err != nil {
+	if _, err := m.client.PostTo(route, msg); err != nil {
 		return err
 	}
 	return nil
 }
 
+// ダイジェストを通知する route の場合、urgent でなければ個別に通知せず entry を記録する
+// (msg が nil の Event も記録する)
+// 通知を控える時間帯の場合、urgent でなければ時間帯が終わるまで溜めておく
+func (m *Manager) Deliver(route string, msg *bytes.Buffer, urgent bool, entry *builder.DigestEntry) error {
+	now := time.Now()
+	if m.digest != nil {
+		m.releaseDigest(now)
+		// urgent な Event も概要には含める
+		if entry != nil && m.digest.Enabled(route) {
+			if err := m.digest.Add(route, entry, now); err != nil {
+				return err
+			}
+			if msg == nil || !urgent {
+				fmt.Printf("Recorded (digest): route: %s\n", route)
+				return nil
+			}
+		}
+	}
+	if m.schedule == nil {
+		return m.SendTo(route, msg)
+	}
+	m.release(now)
+	if msg != nil && !urgent && m.schedule.Quiet(route, now) {
+		fmt.Printf("Queued (quiet hours): route: %s\n", route)
+		return m.schedule.Queue(route, msg.Bytes())
+	}
+	return m.SendTo(route, msg)
+}
+
+// 通知を控える時間帯が終わった route へ、溜まっていた通知をまとめて通知する
+// (Deliver・Flush からのみ呼び出すため、リクエストがなければ時間帯が終わっても通知されない)
+// 失敗した場合は溜め直す (一部の payload は重複して通知される場合がある)
+func (m *Manager) release(now time.Time) {
+	released, err := m.schedule.Release(now)
+	if err != nil {
+		fmt.Printf("Release Failed: %v\n", err)
+		return
+	}
+	routes := make([]string, 0, len(released))
+	for route := range released {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		if err := m.sendQueued(route, released[route]); err != nil {
+			fmt.Printf("Release Failed: %v\n", err)
+			for _, msg := range released[route] {
+				if err := m.schedule.Queue(route, msg); err != nil {
+					fmt.Printf("Queue Failed: %v\n", err)
+				}
+			}
+		}
+	}
+}
+
+// 定期実行 (EventBridge) から呼び出し、通知する時刻になったダイジェスト・溜まっていた通知を送る
+func (m *Manager) Flush() error {
+	now := time.Now()
+	if m.digest != nil {
+		m.releaseDigest(now)
+	}
+	if m.schedule != nil {
+		m.release(now)
+	}
+	return nil
+}
+
+// 通知する時刻になった route へダイジェストを通知する
+// 失敗した場合は概要を戻し、次回その後に記録した Event とまとめて通知する
+func (m *Manager) releaseDigest(now time.Time) {
+	summaries, err := m.digest.Release(now)
+	if err != nil {
+		fmt.Printf("Digest Failed: %v\n", err)
+		return
+	}
+	for _, s := range summaries {
+		msg, err := builder.BuildDigest(s.Route, s.From, s.To, s.Entries)
+		if err == nil {
+			err = m.SendTo(s.Route, msg)
+		}
+		if err != nil {
+			fmt.Printf("Digest Failed: %v\n", err)
+			if err := m.digest.Restore(s); err != nil {
+				fmt.Printf("Digest Restore Failed: %v\n", err)
+			}
+		}
+	}
+}
+
+func (m *Manager) sendQueued(route string, msgs [][]byte) error {
+	bufs, err := builder.BuildQueued(route, msgs)
+	if err != nil {
+		return err
+	}
+	for _, buf := range bufs {
+		if err := m.SendTo(route, buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *Manager) ReportErrorIf(err error) error {
 	if err != nil {
 		fmt.Printf("Failed: %v\n", err)
@@ -3,7 +3,10 @@ package outcome
 import (
 	"bytes"
 
+	"github.com/SongCastle/ggnb/income/builder"
 	"github.com/SongCastle/ggnb/outcome/client"
+	"github.com/SongCastle/ggnb/outcome/digest"
+	"github.com/SongCastle/ggnb/outcome/schedule"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -11,7 +14,7 @@ type MockedOutcomeManager struct {
 	mock.Mock
 }
 
-func (om *MockedOutcomeManager) Init(_ client.AbstractClient) {
+func (om *MockedOutcomeManager) Init(_ client.AbstractClient, _ schedule.AbstractSchedule, _ digest.AbstractDigest) {
 }
 
 func (om *MockedOutcomeManager) Send(msg *bytes.Buffer) error {
@@ -19,6 +22,21 @@ func (om *MockedOutcomeManager) Send(msg *bytes.Buffer) error {
 	return args.Error(0)
 }
 
+func (om *MockedOutcomeManager) SendTo(route string, msg *bytes.Buffer) error {
+	args := om.Called(route, msg)
+	return args.Error(0)
+}
+
+func (om *MockedOutcomeManager) Deliver(route string, msg *bytes.Buffer, urgent bool, entry *builder.DigestEntry) error {
+	args := om.Called(route, msg, urgent, entry)
+	return args.Error(0)
+}
+
+func (om *MockedOutcomeManager) Flush() error {
+	args := om.Called()
+	return args.Error(0)
+}
+
 func (om *MockedOutcomeManager) ReportErrorIf(err error) error {
 	args := om.Called(err)
 	return args.Error(0)
@@ -4,15 +4,19 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/SongCastle/ggnb/income/builder"
 	"github.com/SongCastle/ggnb/outcome/client"
+	"github.com/SongCastle/ggnb/outcome/digest"
+	"github.com/SongCastle/ggnb/outcome/schedule"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestNewManager(t *testing.T) {
 	t.Parallel()
-	m := NewManager(&client.MockedClient{})
+	m := NewManager(&client.MockedClient{}, nil, nil)
 	_, ok := m.(AbstractManager)
 	assert.True(t, ok)
 }
@@ -21,9 +25,13 @@ func TestManagerInit(t *testing.T) {
 	t.Parallel()
 
 	c := &client.MockedClient{}
+	s := &schedule.MockedSchedule{}
+	d := &digest.MockedDigest{}
 	m := &Manager{}
-	m.Init(c)
+	m.Init(c, s, d)
 	assert.IsType(t, m.client, c)
+	assert.IsType(t, m.schedule, s)
+	assert.IsType(t, m.digest, d)
 }
 
 func TestManagerSend(t *testing.T) {
@@ -33,7 +41,7 @@ func TestManagerSend(t *testing.T) {
 
 	t.Run("no errors", func(t *testing.T) {
 		c := &client.MockedClient{}
-		c.On("Post", msg).Return([]byte("ok"), nil)
+		c.On("PostTo", "", msg).Return([]byte("ok"), nil)
 
 		m := &Manager{client: c}
 		err := m.Send(msg)
@@ -45,7 +53,7 @@ func TestManagerSend(t *testing.T) {
 		eemsg := "mocked"
 
 		c := &client.MockedClient{}
-		c.On("Post", msg).Return(b, errors.New(eemsg))
+		c.On("PostTo", "", msg).Return(b, errors.New(eemsg))
 
 		m := &Manager{client: c}
 		err := m.Send(msg)
@@ -53,13 +61,37 @@ func TestManagerSend(t *testing.T) {
 	})
 }
 
+func TestManagerSendTo(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	msg := bytes.NewBufferString(`{"body": "test"}`)
+
+	t.Run("no errors", func(t *testing.T) {
+		c := &client.MockedClient{}
+		c.On("PostTo", "audit", msg).Return([]byte("ok"), nil)
+
+		m := &Manager{client: c}
+		err := m.SendTo("audit", msg)
+		assert.Nil(err)
+	})
+
+	t.Run("nil message", func(t *testing.T) {
+		c := &client.MockedClient{}
+
+		m := &Manager{client: c}
+		err := m.SendTo("audit", nil)
+		assert.Nil(err)
+		c.AssertNotCalled(t, "PostTo", "audit", mock.Anything)
+	})
+}
+
 func TestManagerReportErrorIf(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
 
 	t.Run("no errors", func(t *testing.T) {
 		c := &client.MockedClient{}
-		c.On("Post", mock.AnythingOfType("*bytes.Buffer")).Return([]byte("ok"), nil)
+		c.On("PostTo", "", mock.AnythingOfType("*bytes.Buffer")).Return([]byte("ok"), nil)
 
 		m := &Manager{client: c}
 		err := m.ReportErrorIf(nil)
@@ -71,10 +103,184 @@ func TestManagerReportErrorIf(t *testing.T) {
 		eemsg, eemsg2 := "mocked", "mocked2"
 
 		c := &client.MockedClient{}
-		c.On("Post", mock.AnythingOfType("*bytes.Buffer")).Return(b, errors.New(eemsg2))
+		c.On("PostTo", "", mock.AnythingOfType("*bytes.Buffer")).Return(b, errors.New(eemsg2))
 
 		m := &Manager{client: c}
 		err := m.ReportErrorIf(errors.New(eemsg))
 		assert.EqualError(err, eemsg)
 	})
 }
+
+func TestManagerDeliver(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	msg := bytes.NewBufferString(`{"attachments":[{"title":"test"}]}`)
+
+	t.Run("without schedule", func(t *testing.T) {
+		c := &client.MockedClient{}
+		c.On("PostTo", "audit", msg).Return([]byte("ok"), nil)
+
+		m := &Manager{client: c}
+		assert.Nil(m.Deliver("audit", msg, false, nil))
+		c.AssertExpectations(t)
+	})
+
+	t.Run("quiet", func(t *testing.T) {
+		c := &client.MockedClient{}
+		s := &schedule.MockedSchedule{}
+		s.On("Release", mock.Anything).Return(nil, nil)
+		s.On("Quiet", "audit", mock.Anything).Return(true)
+		s.On("Queue", "audit", msg.Bytes()).Return(nil)
+
+		m := &Manager{client: c, schedule: s}
+		assert.Nil(m.Deliver("audit", msg, false, nil))
+		s.AssertExpectations(t)
+		c.AssertNotCalled(t, "PostTo", "audit", mock.Anything)
+	})
+
+	t.Run("urgent", func(t *testing.T) {
+		c := &client.MockedClient{}
+		c.On("PostTo", "audit", msg).Return([]byte("ok"), nil)
+		s := &schedule.MockedSchedule{}
+		s.On("Release", mock.Anything).Return(nil, nil)
+		s.On("Quiet", "audit", mock.Anything).Return(true)
+
+		m := &Manager{client: c, schedule: s}
+		assert.Nil(m.Deliver("audit", msg, true, nil))
+		c.AssertExpectations(t)
+		s.AssertNotCalled(t, "Queue", "audit", mock.Anything)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		queued := [][]byte{[]byte(`{"attachments":[{"title":"1"}]}`), []byte(`{"attachments":[{"title":"2"}]}`)}
+		c := &client.MockedClient{}
+		c.On("PostTo", "", mock.AnythingOfType("*bytes.Buffer")).Return([]byte("ok"), nil)
+		c.On("PostTo", "audit", msg).Return([]byte("ok"), nil)
+		s := &schedule.MockedSchedule{}
+		s.On("Release", mock.Anything).Return(map[string][][]byte{"": queued}, nil)
+		s.On("Quiet", "audit", mock.Anything).Return(false)
+
+		m := &Manager{client: c, schedule: s}
+		assert.Nil(m.Deliver("audit", msg, false, nil))
+		// 溜まっていた通知は 1 つにまとめる
+		c.AssertNumberOfCalls(t, "PostTo", 2)
+	})
+
+	t.Run("release error", func(t *testing.T) {
+		var b []byte
+		queued := [][]byte{[]byte(`{"attachments":[{"title":"1"}]}`)}
+		c := &client.MockedClient{}
+		c.On("PostTo", "", mock.AnythingOfType("*bytes.Buffer")).Return(b, errors.New("mocked"))
+		s := &schedule.MockedSchedule{}
+		s.On("Release", mock.Anything).Return(map[string][][]byte{"": queued}, nil)
+		s.On("Queue", "", queued[0]).Return(nil)
+		s.On("Quiet", "", mock.Anything).Return(true)
+		s.On("Queue", "", msg.Bytes()).Return(nil)
+
+		m := &Manager{client: c, schedule: s}
+		assert.Nil(m.Deliver("", msg, false, nil))
+		// 送れなかった通知は溜め直す
+		s.AssertCalled(t, "Queue", "", queued[0])
+	})
+}
+
+func TestManagerDeliverDigest(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	msg := bytes.NewBufferString(`{"attachments":[{"title":"test"}]}`)
+	entry := &builder.DigestEntry{Event: "issues", Action: "opened", Title: "test"}
+
+	t.Run("record", func(t *testing.T) {
+		c := &client.MockedClient{}
+		d := &digest.MockedDigest{}
+		d.On("Release", mock.Anything).Return(nil, nil)
+		d.On("Enabled", "team").Return(true)
+		d.On("Add", "team", entry, mock.Anything).Return(nil)
+
+		m := &Manager{client: c, digest: d}
+		assert.Nil(m.Deliver("team", msg, false, entry))
+		d.AssertExpectations(t)
+		c.AssertNotCalled(t, "PostTo", "team", mock.Anything)
+	})
+
+	t.Run("urgent", func(t *testing.T) {
+		c := &client.MockedClient{}
+		c.On("PostTo", "team", msg).Return([]byte("ok"), nil)
+		d := &digest.MockedDigest{}
+		d.On("Release", mock.Anything).Return(nil, nil)
+		d.On("Enabled", "team").Return(true)
+		d.On("Add", "team", entry, mock.Anything).Return(nil)
+
+		m := &Manager{client: c, digest: d}
+		assert.Nil(m.Deliver("team", msg, true, entry))
+		// 個別に通知し、概要にも含める
+		c.AssertExpectations(t)
+		d.AssertExpectations(t)
+	})
+
+	// 通知しない Event も記録する
+	t.Run("without payload", func(t *testing.T) {
+		c := &client.MockedClient{}
+		d := &digest.MockedDigest{}
+		d.On("Release", mock.Anything).Return(nil, nil)
+		d.On("Enabled", "team").Return(true)
+		d.On("Add", "team", entry, mock.Anything).Return(nil)
+
+		m := &Manager{client: c, digest: d}
+		assert.Nil(m.Deliver("team", nil, true, entry))
+		d.AssertExpectations(t)
+		c.AssertNotCalled(t, "PostTo", "team", mock.Anything)
+	})
+
+	t.Run("disabled route", func(t *testing.T) {
+		c := &client.MockedClient{}
+		c.On("PostTo", "audit", msg).Return([]byte("ok"), nil)
+		d := &digest.MockedDigest{}
+		d.On("Release", mock.Anything).Return(nil, nil)
+		d.On("Enabled", "audit").Return(false)
+
+		m := &Manager{client: c, digest: d}
+		assert.Nil(m.Deliver("audit", msg, false, entry))
+		c.AssertExpectations(t)
+		d.AssertNotCalled(t, "Add", "audit", mock.Anything, mock.Anything)
+	})
+}
+
+func TestManagerFlush(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	from := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	c := &client.MockedClient{}
+	c.On("PostTo", "team", mock.AnythingOfType("*bytes.Buffer")).Return([]byte("ok"), nil)
+	d := &digest.MockedDigest{}
+	d.On("Release", mock.Anything).Return([]*digest.Summary{
+		{Route: "team", From: from, To: from.Add(24 * time.Hour), Entries: []*builder.DigestEntry{{Event: "issues", Action: "opened"}}},
+	}, nil)
+	s := &schedule.MockedSchedule{}
+	s.On("Release", mock.Anything).Return(nil, nil)
+
+	m := &Manager{client: c, schedule: s, digest: d}
+	assert.Nil(m.Flush())
+	c.AssertNumberOfCalls(t, "PostTo", 1)
+	s.AssertExpectations(t)
+}
+
+func TestManagerReleaseDigestFailed(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	var b []byte
+	from := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	summary := &digest.Summary{Route: "team", From: from, To: from.Add(24 * time.Hour), Entries: []*builder.DigestEntry{{Event: "release", Action: "published"}}}
+	c := &client.MockedClient{}
+	c.On("PostTo", "team", mock.AnythingOfType("*bytes.Buffer")).Return(b, errors.New("mocked"))
+	d := &digest.MockedDigest{}
+	d.On("Release", mock.Anything).Return([]*digest.Summary{summary}, nil)
+	d.On("Restore", summary).Return(nil)
+
+	m := &Manager{client: c, digest: d}
+	assert.Nil(m.Flush())
+	// 送れなかった概要は戻す
+	d.AssertExpectations(t)
+}
@@ -0,0 +1,205 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SongCastle/ggnb/config"
+	"github.com/SongCastle/ggnb/store"
+)
+
+const (
+	queueKeyPrefix = "quiet_hours_queue:"
+	// 通知が溜まっている route
+	routesKey = "quiet_hours_routes"
+)
+
+// quiet_hours.routes が設定されていない場合は nil を返す
+// cfg は config.Load で検証済みのもの
+func NewSchedule(cfg *config.Config) (AbstractSchedule, error) {
+	if len(cfg.QuietHours.Routes) == 0 {
+		return nil, nil
+	}
+	windows := map[string]*Window{}
+	for route, w := range cfg.QuietHours.Routes {
+		pw, err := ParseWindow(w)
+		if err != nil {
+			return nil, err
+		}
+		windows[route] = pw
+	}
+	s, err := store.NewStore(cfg.Store.Dir)
+	if err != nil {
+		return nil, err
+	}
+	qs := &QuietSchedule{}
+	qs.Init(windows, s)
+	return qs, nil
+}
+
+type AbstractSchedule interface {
+	Init(windows map[string]*Window, s store.AbstractStore)
+	// route が通知を控える時間帯の場合は true
+	Quiet(route string, now time.Time) bool
+	Queue(route string, msg []byte) error
+	// 通知を控える時間帯が終わった route の通知を取り出す
+	Release(now time.Time) (map[string][][]byte, error)
+}
+
+type Window struct {
+	location *time.Location
+	// 0:00 からの分 (同じ場合は時間帯なし)
+	start int
+	end int
+	weekdays map[time.Weekday]bool
+	holidays map[string]bool
+}
+
+func ParseWindow(w config.QuietWindow) (*Window, error) {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	pw := &Window{location: loc, weekdays: map[time.Weekday]bool{}, holidays: map[string]bool{}}
+	if w.Start != "" {
+		if pw.start, err = config.ParseClock(w.Start); err != nil {
+			return nil, err
+		}
+		if pw.end, err = config.ParseClock(w.End); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range w.Weekdays {
+		wd, err := config.ParseWeekday(d)
+		if err != nil {
+			return nil, err
+		}
+		pw.weekdays[wd] = true
+	}
+	for _, d := range w.Holidays {
+		pw.holidays[d] = true
+	}
+	return pw, nil
+}
+
+func (w *Window) Quiet(now time.Time) bool {
+	t := now.In(w.location)
+	if w.weekdays[t.Weekday()] || w.holidays[t.Format(config.HolidayLayout)] {
+		return true
+	}
+	m := t.Hour()*60 + t.Minute()
+	if w.start < w.end {
+		return w.start <= m && m < w.end
+	}
+	if w.start > w.end {
+		return w.start <= m || m < w.end
+	}
+	return false
+}
+
+// 溜まっている通知は store に保存する
+// (Lambda 上ではコンテナが破棄されると失われるため、STORE_DIR は永続化されたディレクトリを推奨)
+type QuietSchedule struct {
+	windows map[string]*Window
+	store store.AbstractStore
+}
+
+func (qs *QuietSchedule) Init(windows map[string]*Window, s store.AbstractStore) {
+	qs.windows = windows
+	qs.store = s
+}
+
+// route ごとの設定がない場合は DefaultDestination の設定に従う
+func (qs *QuietSchedule) window(route string) *Window {
+	if w, ok := qs.windows[strings.ToLower(route)]; ok && route != "" {
+		return w
+	}
+	return qs.windows[config.DefaultDestination]
+}
+
+func (qs *QuietSchedule) Quiet(route string, now time.Time) bool {
+	w := qs.window(route)
+	return w != nil && w.Quiet(now)
+}
+
+func (qs *QuietSchedule) Queue(route string, msg []byte) error {
+	var msgs []json.RawMessage
+	if err := qs.load(queueKeyPrefix+route, &msgs); err != nil {
+		return err
+	}
+	if err := qs.save(queueKeyPrefix+route, append(msgs, json.RawMessage(msg))); err != nil {
+		return err
+	}
+	routes, err := qs.routes()
+	if err != nil {
+		return err
+	}
+	for _, r := range routes {
+		if r == route {
+			return nil
+		}
+	}
+	return qs.save(routesKey, append(routes, route))
+}
+
+func (qs *QuietSchedule) Release(now time.Time) (map[string][][]byte, error) {
+	routes, err := qs.routes()
+	if err != nil {
+		return nil, err
+	}
+	released := map[string][][]byte{}
+	var rest []string
+	for _, route := range routes {
+		if qs.Quiet(route, now) {
+			rest = append(rest, route)
+			continue
+		}
+		var msgs []json.RawMessage
+		if err := qs.load(queueKeyPrefix+route, &msgs); err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			released[route] = append(released[route], []byte(msg))
+		}
+		if err := qs.save(queueKeyPrefix+route, []json.RawMessage{}); err != nil {
+			return nil, err
+		}
+	}
+	if len(released) == 0 {
+		return nil, nil
+	}
+	sort.Strings(rest)
+	if err := qs.save(routesKey, rest); err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+func (qs *QuietSchedule) routes() ([]string, error) {
+	var routes []string
+	err := qs.load(routesKey, &routes)
+	return routes, err
+}
+
+func (qs *QuietSchedule) load(key string, v interface{}) error {
+	value, err := qs.store.Get(key)
+	if err != nil || len(value) == 0 {
+		return err
+	}
+	if err := json.Unmarshal(value, v); err != nil {
+		return errors.New(fmt.Sprintf("broken %s: %v", key, err))
+	}
+	return nil
+}
+
+func (qs *QuietSchedule) save(key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return qs.store.Set(key, value)
+}
@@ -0,0 +1,31 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/SongCastle/ggnb/store"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockedSchedule struct {
+	mock.Mock
+}
+
+func (m *MockedSchedule) Init(windows map[string]*Window, s store.AbstractStore) {
+}
+
+func (m *MockedSchedule) Quiet(route string, now time.Time) bool {
+	args := m.Called(route, now)
+	return args.Bool(0)
+}
+
+func (m *MockedSchedule) Queue(route string, msg []byte) error {
+	args := m.Called(route, msg)
+	return args.Error(0)
+}
+
+func (m *MockedSchedule) Release(now time.Time) (map[string][][]byte, error) {
+	args := m.Called(now)
+	released, _ := args[0].(map[string][][]byte)
+	return released, args.Error(1)
+}
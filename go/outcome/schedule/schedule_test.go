@@ -0,0 +1,124 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SongCastle/ggnb/config"
+	"github.com/SongCastle/ggnb/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSchedule(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	t.Run("without quiet hours", func(t *testing.T) {
+		s, err := NewSchedule(&config.Config{})
+		assert.Nil(err)
+		assert.Nil(s)
+	})
+
+	t.Run("with quiet hours", func(t *testing.T) {
+		cfg := &config.Config{
+			Store: config.Store{Dir: t.TempDir()},
+			QuietHours: config.QuietHours{
+				Routes: map[string]config.QuietWindow{config.DefaultDestination: {Timezone: "Asia/Tokyo", Start: "22:00", End: "08:00"}},
+			},
+		}
+		s, err := NewSchedule(cfg)
+		assert.Nil(err)
+		assert.IsType(s, &QuietSchedule{})
+	})
+}
+
+func TestWindowQuiet(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	jst := time.FixedZone("JST", 9*60*60)
+	w, err := ParseWindow(config.QuietWindow{
+		Timezone: "Asia/Tokyo",
+		Start: "22:00",
+		End: "08:00",
+		Weekdays: []string{"sat", "sun"},
+		Holidays: []string{"2026-01-01"},
+	})
+	assert.Nil(err)
+
+	for at, expected := range map[time.Time]bool{
+		// 月曜日
+		time.Date(2026, 10, 19, 12, 0, 0, 0, jst): false,
+		time.Date(2026, 10, 19, 21, 59, 0, 0, jst): false,
+		time.Date(2026, 10, 19, 22, 0, 0, 0, jst): true,
+		time.Date(2026, 10, 20, 3, 0, 0, 0, jst): true,
+		time.Date(2026, 10, 20, 8, 0, 0, 0, jst): false,
+		// UTC で指定した場合もタイムゾーンで判定する (JST 3:00)
+		time.Date(2026, 10, 19, 18, 0, 0, 0, time.UTC): true,
+		// 土曜日・祝日
+		time.Date(2026, 10, 24, 12, 0, 0, 0, jst): true,
+		time.Date(2026, 1, 1, 12, 0, 0, 0, jst): true,
+	} {
+		assert.Equal(w.Quiet(at), expected, at.String())
+	}
+
+	// 時間帯を指定しない場合は曜日・祝日のみ
+	w, err = ParseWindow(config.QuietWindow{Weekdays: []string{"sun"}})
+	assert.Nil(err)
+	assert.False(w.Quiet(time.Date(2026, 10, 19, 3, 0, 0, 0, time.UTC)))
+	assert.True(w.Quiet(time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC)))
+}
+
+func TestQuietSchedule(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	s, err := store.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	night, err := ParseWindow(config.QuietWindow{Start: "22:00", End: "08:00"})
+	assert.Nil(err)
+	always, err := ParseWindow(config.QuietWindow{Weekdays: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}})
+	assert.Nil(err)
+
+	qs := &QuietSchedule{}
+	qs.Init(map[string]*Window{config.DefaultDestination: night, "audit": always}, s)
+
+	midnight := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	noon := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	t.Run("quiet", func(t *testing.T) {
+		assert.True(qs.Quiet("", midnight))
+		assert.False(qs.Quiet("", noon))
+		assert.True(qs.Quiet("audit", noon))
+		// route ごとの設定がない場合は default
+		assert.False(qs.Quiet("sponsor", noon))
+		assert.True(qs.Quiet("sponsor", midnight))
+	})
+
+	t.Run("queue and release", func(t *testing.T) {
+		assert.Nil(qs.Queue("", []byte(`{"attachments":[{"title":"1"}]}`)))
+		assert.Nil(qs.Queue("", []byte(`{"attachments":[{"title":"2"}]}`)))
+		assert.Nil(qs.Queue("audit", []byte(`{"attachments":[{"title":"3"}]}`)))
+
+		released, err := qs.Release(midnight)
+		assert.Nil(err)
+		assert.Nil(released)
+
+		released, err = qs.Release(noon)
+		assert.Nil(err)
+		assert.Equal(released, map[string][][]byte{
+			"": {[]byte(`{"attachments":[{"title":"1"}]}`), []byte(`{"attachments":[{"title":"2"}]}`)},
+		})
+
+		// 取り出した通知は残らない
+		released, err = qs.Release(noon)
+		assert.Nil(err)
+		assert.Nil(released)
+
+		routes, err := qs.routes()
+		assert.Nil(err)
+		assert.Equal(routes, []string{"audit"})
+	})
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// dir が空の場合は一時ディレクトリに保存する
+func NewStore(dir string) (AbstractStore, error) {
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "ggnb")
+	}
+	s := &FileStore{}
+	if err := s.Init(dir); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+type AbstractStore interface {
+	Init(dir string) error
+	Get(key string) ([]byte, error)
+	Set(key string, value []byte) error
+	Incr(key string) (int, error)
+}
+
+// key ごとに 1 ファイルとして保存する
+// (Lambda 上ではコンテナが破棄されるまでの間のみ保持される)
+// 排他制御はプロセス内のみのため、複数のコンテナで同じ dir を共有すると同時に更新した値が失われる場合がある
+type FileStore struct {
+	dir string
+	mu sync.Mutex
+}
+
+func (fs *FileStore) Init(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	fs.dir = dir
+	return nil
+}
+
+// 存在しない key の場合は nil を返す
+func (fs *FileStore) Get(key string) ([]byte, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.get(key)
+}
+
+func (fs *FileStore) Set(key string, value []byte) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.set(key, value)
+}
+
+func (fs *FileStore) Incr(key string) (int, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	n := 0
+	value, err := fs.get(key)
+	if err != nil {
+		return 0, err
+	}
+	if value != nil {
+		if n, err = strconv.Atoi(strings.TrimSpace(string(value))); err != nil {
+			return 0, err
+		}
+	}
+	n++
+	if err := fs.set(key, []byte(strconv.Itoa(n))); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (fs *FileStore) get(key string) ([]byte, error) {
+	value, err := os.ReadFile(fs.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (fs *FileStore) set(key string, value []byte) error {
+	tmp := fs.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, value, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fs.path(key))
+}
+
+func (fs *FileStore) path(key string) string {
+	return filepath.Join(fs.dir, url.PathEscape(key))
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type MockedStore struct {
+	mock.Mock
+}
+
+func (m *MockedStore) Init(dir string) error {
+	args := m.Called(dir)
+	return args.Error(0)
+}
+
+func (m *MockedStore) Get(key string) ([]byte, error) {
+	args := m.Called(key)
+	value, _ := args[0].([]byte)
+	return value, args.Error(1)
+}
+
+func (m *MockedStore) Set(key string, value []byte) error {
+	args := m.Called(key, value)
+	return args.Error(0)
+}
+
+func (m *MockedStore) Incr(key string) (int, error) {
+	args := m.Called(key)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStore(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	dir := filepath.Join(t.TempDir(), "store")
+	s, err := NewStore(dir)
+	assert.Nil(err)
+	assert.IsType(s, &FileStore{})
+	assert.DirExists(dir)
+}
+
+func TestFileStoreGetSet(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	s := &FileStore{}
+	if err := s.Init(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		value, err := s.Get("stars/Codertocat/Hello-World")
+		assert.Nil(err)
+		assert.Nil(value)
+	})
+
+	t.Run("found", func(t *testing.T) {
+		assert.Nil(s.Set("stars/Codertocat/Hello-World", []byte("test")))
+		value, err := s.Get("stars/Codertocat/Hello-World")
+		assert.Nil(err)
+		assert.Equal(value, []byte("test"))
+	})
+}
+
+func TestFileStoreIncr(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	s := &FileStore{}
+	if err := s.Init(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("increment", func(t *testing.T) {
+		for i := 1; i <= 3; i++ {
+			n, err := s.Incr("counter")
+			assert.Nil(err)
+			assert.Equal(n, i)
+		}
+	})
+
+	t.Run("not a number", func(t *testing.T) {
+		assert.Nil(s.Set("invalid", []byte("xxx")))
+		_, err := s.Incr("invalid")
+		assert.NotNil(err)
+	})
+}